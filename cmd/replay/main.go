@@ -0,0 +1,80 @@
+// Command replay runs the ingestion of a stored feed snapshot again
+// or writes its body to a file, e.g. to be used as an offline test fixture.
+//
+// It reads the same environment as the server:
+//
+//	replay [-dry-run] <snapshot id>
+//	replay -out testdata/feed.xml <snapshot id>
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"go-news-feed/internal/news"
+)
+
+func main() {
+	dryRun := flag.Bool("dry-run", false, "report what would be saved without writing anything")
+	out := flag.String("out", "", "write the body of the snapshot to the file instead of replaying it")
+
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s [-dry-run] [-out file] <snapshot id>\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	id := flag.Arg(0)
+	ctx := context.Background()
+
+	srv := news.NewServer()
+
+	// Init server's dependencies
+	if err := srv.Init(); err != nil {
+		log.Fatalf("error initialising server. err: %v", err)
+	}
+
+	if *out != "" {
+		if err := export(ctx, srv, id, *out); err != nil {
+			log.Fatalf("error exporting snapshot %s. err: %v", id, err)
+		}
+
+		return
+	}
+
+	report, err := srv.Replay(ctx, id, *dryRun)
+	if err != nil {
+		log.Fatalf("error replaying snapshot %s. err: %v", id, err)
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+
+	if err := enc.Encode(report); err != nil {
+		log.Fatalf("error encoding report. err: %v", err)
+	}
+}
+
+// export writes the body of the snapshot to the file
+func export(ctx context.Context, srv *news.Server, id, name string) error {
+	f, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+
+	if err := srv.ExportSnapshot(ctx, id, f); err != nil {
+		return errors.Join(err, f.Close())
+	}
+
+	return f.Close()
+}
@@ -0,0 +1,139 @@
+package news
+
+import (
+	"sync"
+	"time"
+
+	"go-news-feed/pkg/model"
+)
+
+// circuitBreaker keeps a circuit per host so a failing host isn't hammered
+// - closed: requests are allowed, consecutive failures are counted
+// - open: requests are rejected until the cooldown has elapsed
+// - half-open: a single trial request is allowed, its outcome closes or re-opens the circuit
+type circuitBreaker struct {
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time
+
+	mu    sync.Mutex
+	hosts map[string]*circuit
+}
+
+type circuit struct {
+	state    string
+	failures int
+	openedAt time.Time
+	// trial is set while the half-open trial request is in flight
+	trial bool
+}
+
+// newCircuitBreaker - constructor
+func newCircuitBreaker(threshold int, cooldown time.Duration) *circuitBreaker {
+	return &circuitBreaker{
+		threshold: threshold,
+		cooldown:  cooldown,
+		now:       time.Now,
+		hosts:     make(map[string]*circuit),
+	}
+}
+
+// allow returns false if requests to the host must not be sent
+func (b *circuitBreaker) allow(host string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	c := b.circuit(host)
+
+	switch c.state {
+	case model.CircuitOpen:
+		if b.now().Before(c.openedAt.Add(b.cooldown)) {
+			return false
+		}
+
+		c.state = model.CircuitHalfOpen
+		c.trial = true
+
+		return true
+	case model.CircuitHalfOpen:
+		if c.trial {
+			return false
+		}
+
+		c.trial = true
+
+		return true
+	default:
+		return true
+	}
+}
+
+// success closes the circuit of the host
+func (b *circuitBreaker) success(host string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	c := b.circuit(host)
+	c.state = model.CircuitClosed
+	c.failures = 0
+	c.trial = false
+}
+
+// failure opens the circuit once the threshold is reached
+// or straight away if the half-open trial request failed
+func (b *circuitBreaker) failure(host string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	c := b.circuit(host)
+	c.failures++
+	c.trial = false
+
+	if c.state == model.CircuitHalfOpen || (b.threshold > 0 && c.failures >= b.threshold) {
+		c.state = model.CircuitOpen
+		c.openedAt = b.now()
+	}
+}
+
+// release gives up the half-open trial without an outcome
+// e.g. when the request has been cancelled
+func (b *circuitBreaker) release(host string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.circuit(host).trial = false
+}
+
+// status returns the current state of the host circuit
+func (b *circuitBreaker) status(host string) model.CircuitStatus {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	c, ok := b.hosts[host]
+	if !ok {
+		return model.CircuitStatus{Host: host, State: model.CircuitClosed}
+	}
+
+	status := model.CircuitStatus{
+		Host:     host,
+		State:    c.state,
+		Failures: c.failures,
+	}
+
+	if c.state == model.CircuitOpen {
+		retryAt := c.openedAt.Add(b.cooldown)
+		status.RetryAt = &retryAt
+	}
+
+	return status
+}
+
+func (b *circuitBreaker) circuit(host string) *circuit {
+	c, ok := b.hosts[host]
+	if !ok {
+		c = &circuit{state: model.CircuitClosed}
+		b.hosts[host] = c
+	}
+
+	return c
+}
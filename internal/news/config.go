@@ -1,23 +1,121 @@
 package news
 
 import (
+	"time"
+
 	"github.com/kelseyhightower/envconfig"
 )
 
 type Config struct {
 	MongoConfig MongoConfig
 	Server      ServerConfig
+	Scheduler   SchedulerConfig
+	Fetcher     FetcherConfig
+	Cluster     ClusterConfig
+	Extractor   ExtractorConfig
+	Jobs        JobConfig
+	Snapshots   SnapshotConfig
 }
 
 // MongoConfig - config
 type MongoConfig struct {
-	Collection string `envconfig:"MONGO_COLLECTION"`
-	Database   string `envconfig:"MONGO_DATABASE"`
-	URI        string `envconfig:"MONGO_URI"`
+	Collection           string `envconfig:"MONGO_COLLECTION"`
+	SourceCollection     string `envconfig:"MONGO_SOURCE_COLLECTION" default:"sources"`
+	RevisionCollection   string `envconfig:"MONGO_REVISION_COLLECTION" default:"revisions"`
+	RuleCollection       string `envconfig:"MONGO_RULE_COLLECTION" default:"rules"`
+	QuarantineCollection string `envconfig:"MONGO_QUARANTINE_COLLECTION" default:"quarantine"`
+	JobCollection        string `envconfig:"MONGO_JOB_COLLECTION" default:"jobs"`
+	SnapshotCollection   string `envconfig:"MONGO_SNAPSHOT_COLLECTION" default:"snapshots"`
+	Database             string `envconfig:"MONGO_DATABASE"`
+	URI                  string `envconfig:"MONGO_URI"`
 }
 
 type ServerConfig struct {
-	Port int `envconfig:"PORT"`
+	Port            int           `envconfig:"PORT"`
+	ShutdownTimeout time.Duration `envconfig:"SHUTDOWN_TIMEOUT" default:"30s"`
+}
+
+// SchedulerConfig - config for the background polling of sources
+type SchedulerConfig struct {
+	Enabled bool `envconfig:"SCHEDULER_ENABLED" default:"true"`
+	// Interval is used for sources without their own poll interval
+	Interval time.Duration `envconfig:"SCHEDULER_INTERVAL" default:"15m"`
+	// Jitter is the max random delay added to every next run
+	// so sources sharing the same interval don't run all at once
+	Jitter time.Duration `envconfig:"SCHEDULER_JITTER" default:"1m"`
+	// Tick is how often the scheduler checks for sources due to run
+	Tick time.Duration `envconfig:"SCHEDULER_TICK" default:"30s"`
+}
+
+// FetcherConfig - config for fetching feeds
+type FetcherConfig struct {
+	// Workers is the max number of feeds fetched concurrently
+	Workers int `envconfig:"FETCH_WORKERS" default:"4"`
+	// Timeout is applied to each source independently
+	Timeout time.Duration `envconfig:"FETCH_TIMEOUT" default:"30s"`
+	// MaxBytes is the max size of a feed body
+	MaxBytes int64 `envconfig:"FETCH_MAX_BYTES" default:"10485760"`
+	// Retries is the number of retries of timeouts, 5xx and 429 responses
+	Retries int `envconfig:"FETCH_RETRIES" default:"2"`
+	// Backoff is the delay before the first retry, it doubles on every retry up to MaxBackoff
+	Backoff    time.Duration `envconfig:"FETCH_BACKOFF" default:"500ms"`
+	MaxBackoff time.Duration `envconfig:"FETCH_MAX_BACKOFF" default:"30s"`
+	// BreakerThreshold is the number of consecutive failed fetches that opens a host circuit
+	BreakerThreshold int `envconfig:"FETCH_BREAKER_THRESHOLD" default:"5"`
+	// BreakerCooldown is how long a host circuit stays open before a trial fetch
+	BreakerCooldown time.Duration `envconfig:"FETCH_BREAKER_COOLDOWN" default:"5m"`
+	// SecretPrefix is the prefix of the environment variables sources can reference as secrets
+	// so a source can't send any other variable of the server to a feed host
+	SecretPrefix string `envconfig:"FETCH_SECRET_PREFIX" default:"FEED_SECRET_"`
+	// HostInterval is the min time between two requests to the same host, feeds and pages alike
+	HostInterval time.Duration `envconfig:"FETCH_HOST_INTERVAL" default:"1s"`
+	// HostConcurrency is the max number of requests in flight to the same host, unlimited if 0
+	HostConcurrency int `envconfig:"FETCH_HOST_CONCURRENCY" default:"2"`
+}
+
+// ClusterConfig - config for grouping near duplicate articles into stories
+type ClusterConfig struct {
+	// MaxDistance is the max number of different fingerprint bits of two versions of a story
+	// distances above 3 may miss some duplicates as candidates are looked up by 16 bits bands
+	MaxDistance int `envconfig:"CLUSTER_MAX_DISTANCE" default:"3"`
+	// Window is how far apart two versions of a story can be published
+	Window time.Duration `envconfig:"CLUSTER_WINDOW" default:"48h"`
+}
+
+// ExtractorConfig - config for extracting the body of articles from their linked page
+type ExtractorConfig struct {
+	// Workers is the max number of pages fetched concurrently
+	Workers int `envconfig:"EXTRACT_WORKERS" default:"4"`
+	// Timeout is applied to each page independently
+	Timeout time.Duration `envconfig:"EXTRACT_TIMEOUT" default:"10s"`
+	// MaxBytes is the max size of a page
+	MaxBytes int64 `envconfig:"EXTRACT_MAX_BYTES" default:"5242880"`
+	// MaxArticles is the max number of articles extracted per source on every load
+	MaxArticles int `envconfig:"EXTRACT_MAX_ARTICLES" default:"50"`
+	// WordsPerMinute is used to estimate the reading time
+	WordsPerMinute int `envconfig:"EXTRACT_WORDS_PER_MINUTE" default:"200"`
+	// RobotsTTL is how long the robots.txt of a host is cached
+	RobotsTTL time.Duration `envconfig:"EXTRACT_ROBOTS_TTL" default:"24h"`
+}
+
+// JobConfig - config for running the asynchronous load jobs
+type JobConfig struct {
+	// Workers is the max number of jobs run concurrently
+	Workers int `envconfig:"JOB_WORKERS" default:"1"`
+	// PollInterval is how often queued jobs are looked up
+	PollInterval time.Duration `envconfig:"JOB_POLL_INTERVAL" default:"1s"`
+	// Timeout is applied to each job independently
+	Timeout time.Duration `envconfig:"JOB_TIMEOUT" default:"30m"`
+	// Lease is how long a running job is held by its instance without being renewed
+	// jobs whose lease has expired are failed by any instance, it's renewed every third of it
+	Lease time.Duration `envconfig:"JOB_LEASE" default:"1m"`
+}
+
+// SnapshotConfig - config for archiving the raw body of the fetched feeds
+type SnapshotConfig struct {
+	Enabled bool `envconfig:"SNAPSHOT_ENABLED" default:"true"`
+	// Retention is how long snapshots are kept
+	Retention time.Duration `envconfig:"SNAPSHOT_RETENTION" default:"168h"`
 }
 
 func newConfig() (Config, error) {
@@ -2,8 +2,10 @@ package news
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
 
 	"github.com/go-playground/validator/v10"
 
@@ -16,10 +18,10 @@ type endpoint struct {
 }
 
 // newEndpoint - constructor
-func newEndpoint(service Service) *endpoint {
+func newEndpoint(service Service, validator *validator.Validate) *endpoint {
 	return &endpoint{
 		service:   service,
-		validator: validator.New(),
+		validator: validator,
 	}
 }
 
@@ -30,20 +32,492 @@ func (e *endpoint) init() *http.ServeMux {
 	// Routes
 	mux.HandleFunc("GET /find", e.find)
 	mux.HandleFunc("GET /load", e.load)
+	mux.HandleFunc("POST /load", e.loadDocument)
+	mux.HandleFunc("POST /loads", e.createLoadJob)
+	mux.HandleFunc("GET /loads", e.findLoadJobs)
+	mux.HandleFunc("GET /loads/{id}", e.findLoadJob)
+	mux.HandleFunc("GET /articles/{id}", e.findArticleByID)
+	mux.HandleFunc("GET /articles/{id}/revisions", e.findRevisions)
+	mux.HandleFunc("GET /stories/{id}", e.findStory)
+	mux.HandleFunc("POST /sources", e.createSource)
+	mux.HandleFunc("GET /sources", e.findSources)
+	mux.HandleFunc("GET /sources/{id}", e.findSourceByID)
+	mux.HandleFunc("PUT /sources/{id}", e.updateSource)
+	mux.HandleFunc("DELETE /sources/{id}", e.deleteSource)
+	mux.HandleFunc("GET /sources/{id}/status", e.findSourceStatus)
+	mux.HandleFunc("POST /rules", e.createRule)
+	mux.HandleFunc("GET /rules", e.findRules)
+	mux.HandleFunc("POST /rules/dry-run", e.dryRunRule)
+	mux.HandleFunc("GET /rules/{id}", e.findRuleByID)
+	mux.HandleFunc("PUT /rules/{id}", e.updateRule)
+	mux.HandleFunc("DELETE /rules/{id}", e.deleteRule)
+	mux.HandleFunc("GET /quarantine", e.findQuarantine)
+	mux.HandleFunc("GET /quarantine/{id}", e.findQuarantinedItem)
+	mux.HandleFunc("POST /quarantine/{id}/reprocess", e.reprocessQuarantinedItem)
+	mux.HandleFunc("DELETE /quarantine/{id}", e.discardQuarantinedItem)
+	mux.HandleFunc("GET /snapshots", e.findSnapshots)
+	mux.HandleFunc("GET /snapshots/{id}", e.findSnapshot)
+	mux.HandleFunc("GET /snapshots/{id}/body", e.findSnapshotBody)
+	mux.HandleFunc("POST /snapshots/{id}/replay", e.replaySnapshot)
 
 	return mux
 }
 
 func (e endpoint) find(w http.ResponseWriter, r *http.Request) {
+	var fr model.FindRequest
+	if !e.decodeForm(w, r, &fr) {
+		return
+	}
+
+	// Find
+	response, err := e.service.Find(r.Context(), fr)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to find news: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	response.Articles = renderArticles(response.Articles, fr.Format)
+
+	// Encode object as JSON and write to response
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		http.Error(w, fmt.Sprintf("failed to encode response: %v", err), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (e endpoint) load(w http.ResponseWriter, r *http.Request) {
+	var lr model.LoadRequest
+	if !e.decodeForm(w, r, &lr) {
+		return
+	}
+
+	response, err := e.service.Load(r.Context(), lr)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to load news: %v", err), statusCode(err))
+		return
+	}
+
+	// Encode object as JSON and write to response
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		http.Error(w, fmt.Sprintf("failed to encode response: %v", err), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (e endpoint) loadDocument(w http.ResponseWriter, r *http.Request) {
+	// the body is the document so the metadata are only read from the query params
+	var ur model.UploadRequest
+	if !e.decodeValues(w, r.URL.Query(), &ur) {
+		return
+	}
+
+	ur.ContentType = r.Header.Get("Content-Type")
+
+	response, err := e.service.LoadDocument(r.Context(), ur, r.Body)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to load document: %v", err), statusCode(err))
+		return
+	}
+
+	encodeResponse(w, http.StatusOK, response)
+}
+
+func (e endpoint) createLoadJob(w http.ResponseWriter, r *http.Request) {
+	var lr model.LoadRequest
+	if !e.decodeForm(w, r, &lr) {
+		return
+	}
+
+	response, err := e.service.CreateLoadJob(r.Context(), lr)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to create load job: %v", err), statusCode(err))
+		return
+	}
+
+	w.Header().Set("Location", "/loads/"+url.PathEscape(response.ID))
+	encodeResponse(w, http.StatusAccepted, response)
+}
+
+func (e endpoint) findLoadJobs(w http.ResponseWriter, r *http.Request) {
+	var jr model.JobRequest
+	if !e.decodeForm(w, r, &jr) {
+		return
+	}
+
+	response, err := e.service.FindLoadJobs(r.Context(), jr)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to find load jobs: %v", err), statusCode(err))
+		return
+	}
+
+	encodeResponse(w, http.StatusOK, response)
+}
+
+func (e endpoint) findLoadJob(w http.ResponseWriter, r *http.Request) {
+	response, err := e.service.FindLoadJob(r.Context(), r.PathValue("id"))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to find load job: %v", err), statusCode(err))
+		return
+	}
+
+	encodeResponse(w, http.StatusOK, response)
+}
+
+func (e endpoint) findArticleByID(w http.ResponseWriter, r *http.Request) {
+	format, ok := e.decodeFormat(w, r)
+	if !ok {
+		return
+	}
+
+	response, err := e.service.FindArticleByID(r.Context(), r.PathValue("id"))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to find article: %v", err), statusCode(err))
+		return
+	}
+
+	encodeResponse(w, http.StatusOK, renderArticle(response, format))
+}
+
+func (e endpoint) findRevisions(w http.ResponseWriter, r *http.Request) {
+	format, ok := e.decodeFormat(w, r)
+	if !ok {
+		return
+	}
+
+	response, err := e.service.FindRevisions(r.Context(), r.PathValue("id"))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to find article revisions: %v", err), statusCode(err))
+		return
+	}
+
+	encodeResponse(w, http.StatusOK, renderChanges(response, format))
+}
+
+func (e endpoint) findStory(w http.ResponseWriter, r *http.Request) {
+	format, ok := e.decodeFormat(w, r)
+	if !ok {
+		return
+	}
+
+	response, err := e.service.FindStory(r.Context(), r.PathValue("id"))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to find story: %v", err), statusCode(err))
+		return
+	}
+
+	response.Articles = renderArticles(response.Articles, format)
+
+	encodeResponse(w, http.StatusOK, response)
+}
+
+func (e endpoint) createSource(w http.ResponseWriter, r *http.Request) {
+	source, ok := e.decodeSource(w, r)
+	if !ok {
+		return
+	}
+
+	response, err := e.service.CreateSource(r.Context(), source)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to create source: %v", err), statusCode(err))
+		return
+	}
+
+	encodeResponse(w, http.StatusCreated, response)
+}
+
+func (e endpoint) findSources(w http.ResponseWriter, r *http.Request) {
+	response, err := e.service.FindSources(r.Context())
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to find sources: %v", err), statusCode(err))
+		return
+	}
+
+	encodeResponse(w, http.StatusOK, response)
+}
+
+func (e endpoint) findSourceByID(w http.ResponseWriter, r *http.Request) {
+	response, err := e.service.FindSourceByID(r.Context(), r.PathValue("id"))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to find source: %v", err), statusCode(err))
+		return
+	}
+
+	encodeResponse(w, http.StatusOK, response)
+}
+
+func (e endpoint) updateSource(w http.ResponseWriter, r *http.Request) {
+	source, ok := e.decodeSource(w, r)
+	if !ok {
+		return
+	}
+
+	source.ID = r.PathValue("id")
+
+	response, err := e.service.UpdateSource(r.Context(), source)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to update source: %v", err), statusCode(err))
+		return
+	}
+
+	encodeResponse(w, http.StatusOK, response)
+}
+
+func (e endpoint) deleteSource(w http.ResponseWriter, r *http.Request) {
+	if err := e.service.DeleteSource(r.Context(), r.PathValue("id")); err != nil {
+		http.Error(w, fmt.Sprintf("failed to delete source: %v", err), statusCode(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (e endpoint) findSourceStatus(w http.ResponseWriter, r *http.Request) {
+	response, err := e.service.FindSourceStatus(r.Context(), r.PathValue("id"))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to find source status: %v", err), statusCode(err))
+		return
+	}
+
+	encodeResponse(w, http.StatusOK, response)
+}
+
+func (e endpoint) createRule(w http.ResponseWriter, r *http.Request) {
+	rule, ok := e.decodeRule(w, r)
+	if !ok {
+		return
+	}
+
+	response, err := e.service.CreateRule(r.Context(), rule)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to create rule: %v", err), statusCode(err))
+		return
+	}
+
+	encodeResponse(w, http.StatusCreated, response)
+}
+
+func (e endpoint) findRules(w http.ResponseWriter, r *http.Request) {
+	response, err := e.service.FindRules(r.Context(), r.URL.Query().Get("sourceId"))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to find rules: %v", err), statusCode(err))
+		return
+	}
+
+	encodeResponse(w, http.StatusOK, response)
+}
+
+func (e endpoint) findRuleByID(w http.ResponseWriter, r *http.Request) {
+	response, err := e.service.FindRuleByID(r.Context(), r.PathValue("id"))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to find rule: %v", err), statusCode(err))
+		return
+	}
+
+	encodeResponse(w, http.StatusOK, response)
+}
+
+func (e endpoint) updateRule(w http.ResponseWriter, r *http.Request) {
+	rule, ok := e.decodeRule(w, r)
+	if !ok {
+		return
+	}
+
+	rule.ID = r.PathValue("id")
+
+	response, err := e.service.UpdateRule(r.Context(), rule)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to update rule: %v", err), statusCode(err))
+		return
+	}
+
+	encodeResponse(w, http.StatusOK, response)
+}
+
+func (e endpoint) deleteRule(w http.ResponseWriter, r *http.Request) {
+	if err := e.service.DeleteRule(r.Context(), r.PathValue("id")); err != nil {
+		http.Error(w, fmt.Sprintf("failed to delete rule: %v", err), statusCode(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (e endpoint) dryRunRule(w http.ResponseWriter, r *http.Request) {
+	format, ok := e.decodeFormat(w, r)
+	if !ok {
+		return
+	}
+
+	rule, ok := e.decodeRule(w, r)
+	if !ok {
+		return
+	}
+
+	response, err := e.service.DryRunRule(r.Context(), rule)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to dry run rule: %v", err), statusCode(err))
+		return
+	}
+
+	response.Articles = renderArticles(response.Articles, format)
+
+	encodeResponse(w, http.StatusOK, response)
+}
+
+// decodeRule decodes and validates the rule from the request body
+// it writes the error response and returns false if the rule is invalid
+func (e endpoint) decodeRule(w http.ResponseWriter, r *http.Request) (model.Rule, bool) {
+	var rule model.Rule
+	if err := json.NewDecoder(r.Body).Decode(&rule); err != nil {
+		http.Error(w, fmt.Sprintf("failed to decode request body: %v", err), http.StatusBadRequest)
+		return model.Rule{}, false
+	}
+
+	if err := e.validator.Struct(rule); err != nil {
+		http.Error(w, fmt.Sprintf("invalid request: %v", err), http.StatusBadRequest)
+		return model.Rule{}, false
+	}
+
+	return rule, true
+}
+
+func (e endpoint) findQuarantine(w http.ResponseWriter, r *http.Request) {
+	var qr model.QuarantineRequest
+	if !e.decodeForm(w, r, &qr) {
+		return
+	}
+
+	response, err := e.service.FindQuarantine(r.Context(), qr)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to find quarantined items: %v", err), statusCode(err))
+		return
+	}
+
+	response.Items = renderQuarantinedItems(response.Items, qr.Format)
+
+	encodeResponse(w, http.StatusOK, response)
+}
+
+func (e endpoint) findQuarantinedItem(w http.ResponseWriter, r *http.Request) {
+	format, ok := e.decodeFormat(w, r)
+	if !ok {
+		return
+	}
+
+	response, err := e.service.FindQuarantinedItem(r.Context(), r.PathValue("id"))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to find quarantined item: %v", err), statusCode(err))
+		return
+	}
+
+	response.Article = renderArticle(response.Article, format)
+
+	encodeResponse(w, http.StatusOK, response)
+}
+
+func (e endpoint) reprocessQuarantinedItem(w http.ResponseWriter, r *http.Request) {
+	response, err := e.service.ReprocessQuarantinedItem(r.Context(), r.PathValue("id"))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to reprocess quarantined item: %v", err), statusCode(err))
+		return
+	}
+
+	encodeResponse(w, http.StatusOK, renderArticle(response, formatHTML))
+}
+
+func (e endpoint) discardQuarantinedItem(w http.ResponseWriter, r *http.Request) {
+	if err := e.service.DiscardQuarantinedItem(r.Context(), r.PathValue("id")); err != nil {
+		http.Error(w, fmt.Sprintf("failed to discard quarantined item: %v", err), statusCode(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (e endpoint) findSnapshots(w http.ResponseWriter, r *http.Request) {
+	var sr model.SnapshotRequest
+	if !e.decodeForm(w, r, &sr) {
+		return
+	}
+
+	response, err := e.service.FindSnapshots(r.Context(), sr)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to find snapshots: %v", err), statusCode(err))
+		return
+	}
+
+	encodeResponse(w, http.StatusOK, response)
+}
+
+func (e endpoint) findSnapshot(w http.ResponseWriter, r *http.Request) {
+	response, _, err := e.service.FindSnapshot(r.Context(), r.PathValue("id"))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to find snapshot: %v", err), statusCode(err))
+		return
+	}
+
+	encodeResponse(w, http.StatusOK, response)
+}
+
+// findSnapshotBody writes the body of the feed as it was fetched
+// it's sent as an attachment so the feed content isn't rendered by browsers
+func (e endpoint) findSnapshotBody(w http.ResponseWriter, r *http.Request) {
+	snapshot, body, err := e.service.FindSnapshot(r.Context(), r.PathValue("id"))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to find snapshot: %v", err), statusCode(err))
+		return
+	}
+
+	contentType := http.Header(snapshot.Header).Get("Content-Type")
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", "attachment")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+
+	if _, err := w.Write(body); err != nil {
+		http.Error(w, fmt.Sprintf("failed to write response: %v", err), http.StatusInternalServerError)
+		return
+	}
+}
+
+func (e endpoint) replaySnapshot(w http.ResponseWriter, r *http.Request) {
+	var rr model.ReplayRequest
+	if !e.decodeForm(w, r, &rr) {
+		return
+	}
+
+	response, err := e.service.ReplaySnapshot(r.Context(), r.PathValue("id"), rr.DryRun)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to replay snapshot: %v", err), statusCode(err))
+		return
+	}
+
+	encodeResponse(w, http.StatusOK, response)
+}
+
+// decodeForm decodes and validates the query params (or form) into the request provided
+// it writes the error response and returns false if the request is invalid
+func (e endpoint) decodeForm(w http.ResponseWriter, r *http.Request, request any) bool {
 	// Parse request body
 	if err := r.ParseForm(); err != nil {
 		http.Error(w, fmt.Sprintf("failed to parse request body: %v", err), http.StatusBadRequest)
-		return
+		return false
 	}
 
+	return e.decodeValues(w, r.Form, request)
+}
+
+// decodeValues decodes and validates the request from the form or query values
+// it writes the error response and returns false if the request is invalid
+func (e endpoint) decodeValues(w http.ResponseWriter, values url.Values, request any) bool {
 	// Transformation from map[string][]string to map[string]string:
 	m := map[string]string{}
-	for k, v := range r.Form {
+	for k, v := range values {
 		m[k] = v[0]
 	}
 
@@ -51,48 +525,80 @@ func (e endpoint) find(w http.ResponseWriter, r *http.Request) {
 	data, err := json.Marshal(m)
 	if err != nil {
 		http.Error(w, fmt.Sprintf("failed to marshal request body: %v", err), http.StatusBadRequest)
-		return
+		return false
 	}
 
 	// Decode request body into a new object
-	var fr model.FindRequest
-	if err := json.Unmarshal(data, &fr); err != nil {
+	if err := json.Unmarshal(data, request); err != nil {
 		http.Error(w, fmt.Sprintf("failed to decode request body: %v", err), http.StatusBadRequest)
-		return
+		return false
 	}
 
 	// Validate the request
-	if err := e.validator.Struct(fr); err != nil {
+	if err := e.validator.Struct(request); err != nil {
 		http.Error(w, fmt.Sprintf("invalid request: %v", err), http.StatusBadRequest)
-		return
+		return false
 	}
 
-	// Find
-	response, err := e.service.Find(r.Context(), fr)
-	if err != nil {
-		http.Error(w, fmt.Sprintf("failed to find news: %v", err), http.StatusInternalServerError)
-		return
+	return true
+}
+
+// decodeSource decodes and validates the source from the request body
+// it writes the error response and returns false if the source is invalid
+func (e endpoint) decodeSource(w http.ResponseWriter, r *http.Request) (model.Source, bool) {
+	var source model.Source
+	if err := json.NewDecoder(r.Body).Decode(&source); err != nil {
+		http.Error(w, fmt.Sprintf("failed to decode request body: %v", err), http.StatusBadRequest)
+		return model.Source{}, false
 	}
 
-	// Encode object as JSON and write to response
-	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(response); err != nil {
-		http.Error(w, fmt.Sprintf("failed to encode response: %v", err), http.StatusInternalServerError)
-		return
+	if err := e.validator.Struct(source); err != nil {
+		http.Error(w, fmt.Sprintf("invalid request: %v", err), http.StatusBadRequest)
+		return model.Source{}, false
 	}
+
+	return source, true
 }
 
-func (e endpoint) load(w http.ResponseWriter, r *http.Request) {
-	response, err := e.service.Load(r.Context(), r.URL.Query().Get("feedUrl"))
-	if err != nil {
-		http.Error(w, fmt.Sprintf("failed to load news: %v", err), http.StatusInternalServerError)
-		return
+// decodeFormat returns the format requested, html by default
+// it writes the error response and returns false if the format is invalid
+func (e endpoint) decodeFormat(w http.ResponseWriter, r *http.Request) (string, bool) {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		return formatHTML, true
 	}
 
-	// Encode object as JSON and write to response
+	if err := e.validator.Var(format, "oneof=html text"); err != nil {
+		http.Error(w, fmt.Sprintf("invalid format: %s", format), http.StatusBadRequest)
+		return "", false
+	}
+
+	return format, true
+}
+
+// encodeResponse encodes object as JSON and write to response
+func encodeResponse(w http.ResponseWriter, code int, response any) {
 	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+
 	if err := json.NewEncoder(w).Encode(response); err != nil {
 		http.Error(w, fmt.Sprintf("failed to encode response: %v", err), http.StatusInternalServerError)
 		return
 	}
 }
+
+// statusCode maps service errors to HTTP status codes
+func statusCode(err error) int {
+	switch {
+	case errors.Is(err, ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, ErrInvalidRequest):
+		return http.StatusBadRequest
+	case errors.Is(err, ErrAlreadyExists):
+		return http.StatusConflict
+	case errors.Is(err, ErrInvalidFeed):
+		return http.StatusUnprocessableEntity
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -2,13 +2,18 @@ package news
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"strings"
 	"testing"
 
+	"github.com/go-playground/validator/v10"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/suite"
 
@@ -20,12 +25,14 @@ type TestSuite struct {
 	router      *http.ServeMux
 	serviceMock *MockService
 	article     model.Article
+	source      model.Source
+	report      model.LoadReport
 }
 
 func (suite *TestSuite) SetupSuite() {
 	ctrl := gomock.NewController(suite.T())
 	suite.serviceMock = NewMockService(ctrl)
-	suite.router = newEndpoint(suite.serviceMock).init()
+	suite.router = newEndpoint(suite.serviceMock, validator.New()).init()
 }
 
 func (suite *TestSuite) SetupTest() {
@@ -35,6 +42,16 @@ func (suite *TestSuite) SetupTest() {
 		Descriptiopn: "test description",
 		Link:         "test link",
 	}
+
+	suite.source = model.Source{
+		ID:       "test id",
+		Category: model.CategoryTechnology,
+		FeedURL:  "https://example.com/rss.xml",
+		Provider: "test provider",
+	}
+
+	suite.report = model.LoadReport{}
+	suite.report.Add(model.SourceReport{Source: suite.source, Fetched: 1, New: 1})
 }
 
 func (suite *TestSuite) TestFind() {
@@ -100,6 +117,26 @@ func (suite *TestSuite) TestFind() {
 	}
 }
 
+func (suite *TestSuite) TestFindFilters() {
+	expected := model.FindRequest{Author: "Weather Team", Tag: "UK", HasImage: true, Collapse: model.CollapseStory}
+
+	suite.serviceMock.EXPECT().Find(gomock.Any(), expected).Return(model.FindResponse{Criteria: expected}, nil)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/find?author=Weather+Team&tag=UK&hasImage=true&collapse=story", nil)
+
+	suite.router.ServeHTTP(w, r)
+
+	suite.Equal(http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	r = httptest.NewRequest(http.MethodGet, "/find?collapse=provider", nil)
+
+	suite.router.ServeHTTP(w, r)
+
+	suite.Equal(http.StatusBadRequest, w.Code)
+}
+
 func (suite *TestSuite) TestLoad() {
 	testCases := []struct {
 		name         string
@@ -111,26 +148,41 @@ func (suite *TestSuite) TestLoad() {
 		{
 			name: "LoadInternalServerError",
 			mockCalls: func() {
-				suite.serviceMock.EXPECT().Load(gomock.Any(), gomock.Any()).Return(nil, errors.New("internal server error"))
+				suite.serviceMock.EXPECT().Load(gomock.Any(), gomock.Any()).Return(model.LoadReport{}, errors.New("internal server error"))
 			},
 			expectedCode: http.StatusInternalServerError,
 		},
 		{
 			name: "LoadSuccess",
 			mockCalls: func() {
-				suite.serviceMock.EXPECT().Load(gomock.Any(), gomock.Any()).Return([]model.Article{suite.article}, nil)
+				suite.serviceMock.EXPECT().Load(gomock.Any(), gomock.Any()).Return(suite.report, nil)
 			},
 			expectedCode: http.StatusOK,
-			expected:     []model.Article{suite.article},
+			expected:     suite.report,
 		},
 		{
 			name:  "LoadSuccessWithURL",
 			given: "test",
 			mockCalls: func() {
-				suite.serviceMock.EXPECT().Load(gomock.Any(), "test").Return([]model.Article{suite.article}, nil)
+				suite.serviceMock.EXPECT().Load(gomock.Any(), model.LoadRequest{FeedURL: "test"}).Return(suite.report, nil)
 			},
 			expectedCode: http.StatusOK,
-			expected:     []model.Article{suite.article},
+			expected:     suite.report,
+		},
+		{
+			name:  "LoadDryRun",
+			given: "test&dryRun=true",
+			mockCalls: func() {
+				suite.serviceMock.EXPECT().Load(gomock.Any(), model.LoadRequest{FeedURL: "test", DryRun: true}).Return(suite.report, nil)
+			},
+			expectedCode: http.StatusOK,
+			expected:     suite.report,
+		},
+		{
+			name:         "LoadInvalidDryRun",
+			given:        "test&dryRun=maybe",
+			mockCalls:    func() {},
+			expectedCode: http.StatusBadRequest,
 		},
 	}
 
@@ -149,15 +201,738 @@ func (suite *TestSuite) TestLoad() {
 				res := w.Result()
 				defer res.Body.Close()
 
-				var articles []model.Article
+				var report model.LoadReport
 
 				decoder := json.NewDecoder(res.Body)
-				err := decoder.Decode(&articles)
+				err := decoder.Decode(&report)
 				suite.NoError(err)
 
-				suite.NotEmpty(articles)
-				suite.Equal(tc.expected, articles)
+				suite.NotEmpty(report)
+				suite.Equal(tc.expected, report)
+			}
+		})
+	}
+}
+
+func (suite *TestSuite) TestCreateSource() {
+	testCases := []struct {
+		name         string
+		given        model.Source
+		mockCalls    func()
+		expectedCode int
+	}{
+		{
+			name:         "CreateSourceInvalidFeedURL",
+			given:        model.Source{Category: model.CategoryUK, FeedURL: "not a url", Provider: model.ProviderBBC},
+			mockCalls:    func() {},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "CreateSourceMissingProvider",
+			given:        model.Source{Category: model.CategoryUK, FeedURL: "https://example.com/rss.xml"},
+			mockCalls:    func() {},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:  "CreateSourceInvalidLinkPolicy",
+			given: suite.source,
+			mockCalls: func() {
+				suite.serviceMock.EXPECT().CreateSource(gomock.Any(), gomock.Any()).Return(model.Source{}, ErrInvalidRequest)
+			},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:  "CreateSourceConflict",
+			given: suite.source,
+			mockCalls: func() {
+				suite.serviceMock.EXPECT().CreateSource(gomock.Any(), gomock.Any()).Return(model.Source{}, ErrAlreadyExists)
+			},
+			expectedCode: http.StatusConflict,
+		},
+		{
+			name:  "CreateSourceSuccess",
+			given: suite.source,
+			mockCalls: func() {
+				suite.serviceMock.EXPECT().CreateSource(gomock.Any(), gomock.Any()).Return(suite.source, nil)
+			},
+			expectedCode: http.StatusCreated,
+		},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			tc.mockCalls()
+
+			b, err := json.Marshal(tc.given)
+			suite.NoError(err)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/sources", bytes.NewReader(b))
+
+			suite.router.ServeHTTP(w, r)
+
+			suite.Equal(tc.expectedCode, w.Code)
+		})
+	}
+}
+
+func (suite *TestSuite) TestSourceByID() {
+	testCases := []struct {
+		name         string
+		method       string
+		body         any
+		mockCalls    func()
+		expectedCode int
+	}{
+		{
+			name:   "FindSourceNotFound",
+			method: http.MethodGet,
+			mockCalls: func() {
+				suite.serviceMock.EXPECT().FindSourceByID(gomock.Any(), "test id").Return(model.Source{}, ErrNotFound)
+			},
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:   "FindSourceSuccess",
+			method: http.MethodGet,
+			mockCalls: func() {
+				suite.serviceMock.EXPECT().FindSourceByID(gomock.Any(), "test id").Return(suite.source, nil)
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:   "UpdateSourceSuccess",
+			method: http.MethodPut,
+			body:   suite.source,
+			mockCalls: func() {
+				suite.serviceMock.EXPECT().UpdateSource(gomock.Any(), suite.source).Return(suite.source, nil)
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:   "DeleteSourceNotFound",
+			method: http.MethodDelete,
+			mockCalls: func() {
+				suite.serviceMock.EXPECT().DeleteSource(gomock.Any(), "test id").Return(ErrNotFound)
+			},
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:   "DeleteSourceSuccess",
+			method: http.MethodDelete,
+			mockCalls: func() {
+				suite.serviceMock.EXPECT().DeleteSource(gomock.Any(), "test id").Return(nil)
+			},
+			expectedCode: http.StatusNoContent,
+		},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			tc.mockCalls()
+
+			b, err := json.Marshal(tc.body)
+			suite.NoError(err)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(tc.method, "/sources/test%20id", bytes.NewReader(b))
+
+			suite.router.ServeHTTP(w, r)
+
+			suite.Equal(tc.expectedCode, w.Code)
+		})
+	}
+}
+
+func (suite *TestSuite) TestRules() {
+	rule := model.Rule{Name: "no live blogs", Type: model.RuleBlock, Title: "^Live:"}
+
+	testCases := []struct {
+		name         string
+		method       string
+		target       string
+		body         any
+		mockCalls    func()
+		expectedCode int
+	}{
+		{
+			name:         "CreateRuleInvalidType",
+			method:       http.MethodPost,
+			target:       "/rules",
+			body:         model.Rule{Name: "no live blogs", Type: "deny", Title: "^Live:"},
+			mockCalls:    func() {},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:   "CreateRuleInvalidPattern",
+			method: http.MethodPost,
+			target: "/rules",
+			body:   rule,
+			mockCalls: func() {
+				suite.serviceMock.EXPECT().CreateRule(gomock.Any(), rule).Return(model.Rule{}, ErrInvalidRequest)
+			},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:   "CreateRuleSuccess",
+			method: http.MethodPost,
+			target: "/rules",
+			body:   rule,
+			mockCalls: func() {
+				suite.serviceMock.EXPECT().CreateRule(gomock.Any(), rule).Return(rule, nil)
+			},
+			expectedCode: http.StatusCreated,
+		},
+		{
+			name:   "FindRulesOfSource",
+			method: http.MethodGet,
+			target: "/rules?sourceId=test+id",
+			mockCalls: func() {
+				suite.serviceMock.EXPECT().FindRules(gomock.Any(), "test id").Return([]model.Rule{rule}, nil)
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:   "UpdateRuleNotFound",
+			method: http.MethodPut,
+			target: "/rules/test%20id",
+			body:   rule,
+			mockCalls: func() {
+				updated := rule
+				updated.ID = "test id"
+
+				suite.serviceMock.EXPECT().UpdateRule(gomock.Any(), updated).Return(model.Rule{}, ErrNotFound)
+			},
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:   "DeleteRuleSuccess",
+			method: http.MethodDelete,
+			target: "/rules/test%20id",
+			mockCalls: func() {
+				suite.serviceMock.EXPECT().DeleteRule(gomock.Any(), "test id").Return(nil)
+			},
+			expectedCode: http.StatusNoContent,
+		},
+		{
+			name:   "DryRunRuleSuccess",
+			method: http.MethodPost,
+			target: "/rules/dry-run",
+			body:   rule,
+			mockCalls: func() {
+				suite.serviceMock.EXPECT().DryRunRule(gomock.Any(), rule).Return(model.DryRunResponse{
+					Rule:     rule,
+					Scanned:  1,
+					Matched:  1,
+					Articles: []model.Article{suite.article},
+				}, nil)
+			},
+			expectedCode: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			tc.mockCalls()
+
+			b, err := json.Marshal(tc.body)
+			suite.NoError(err)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(tc.method, tc.target, bytes.NewReader(b))
+
+			suite.router.ServeHTTP(w, r)
+
+			suite.Equal(tc.expectedCode, w.Code)
+		})
+	}
+}
+
+func (suite *TestSuite) TestQuarantine() {
+	item := model.QuarantinedItem{ID: "test id", Source: suite.source, Article: suite.article, Reason: "Title: required"}
+	item.Article.Descriptiopn = `<p>test <script>alert(1)</script>description</p>`
+
+	testCases := []struct {
+		name                string
+		method              string
+		target              string
+		mockCalls           func()
+		expectedCode        int
+		expectedDescription string
+	}{
+		{
+			name:         "FindQuarantineInvalidLimit",
+			method:       http.MethodGet,
+			target:       "/quarantine?limit=5000",
+			mockCalls:    func() {},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:   "FindQuarantineOfSource",
+			method: http.MethodGet,
+			target: "/quarantine?sourceId=test+id&limit=50&page=1",
+			mockCalls: func() {
+				qr := model.QuarantineRequest{SourceID: "test id", Limit: 50, Page: 1}
+				suite.serviceMock.EXPECT().FindQuarantine(gomock.Any(), qr).Return(model.QuarantineResponse{
+					Criteria: qr,
+					Items:    []model.QuarantinedItem{item},
+					Total:    1,
+				}, nil)
+			},
+			expectedCode:        http.StatusOK,
+			expectedDescription: "<p>test description</p>",
+		},
+		{
+			name:         "FindQuarantineInvalidFormat",
+			method:       http.MethodGet,
+			target:       "/quarantine?format=markdown",
+			mockCalls:    func() {},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:   "FindQuarantinedItemNotFound",
+			method: http.MethodGet,
+			target: "/quarantine/test%20id",
+			mockCalls: func() {
+				suite.serviceMock.EXPECT().FindQuarantinedItem(gomock.Any(), "test id").Return(model.QuarantinedItem{}, ErrNotFound)
+			},
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:   "FindQuarantinedItemText",
+			method: http.MethodGet,
+			target: "/quarantine/test%20id?format=text",
+			mockCalls: func() {
+				suite.serviceMock.EXPECT().FindQuarantinedItem(gomock.Any(), "test id").Return(item, nil)
+			},
+			expectedCode:        http.StatusOK,
+			expectedDescription: "test description",
+		},
+		{
+			name:   "ReprocessStillInvalid",
+			method: http.MethodPost,
+			target: "/quarantine/test%20id/reprocess",
+			mockCalls: func() {
+				suite.serviceMock.EXPECT().ReprocessQuarantinedItem(gomock.Any(), "test id").Return(model.Article{}, ErrInvalidRequest)
+			},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:   "ReprocessSuccess",
+			method: http.MethodPost,
+			target: "/quarantine/test%20id/reprocess",
+			mockCalls: func() {
+				suite.serviceMock.EXPECT().ReprocessQuarantinedItem(gomock.Any(), "test id").Return(suite.article, nil)
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:   "DiscardSuccess",
+			method: http.MethodDelete,
+			target: "/quarantine/test%20id",
+			mockCalls: func() {
+				suite.serviceMock.EXPECT().DiscardQuarantinedItem(gomock.Any(), "test id").Return(nil)
+			},
+			expectedCode: http.StatusNoContent,
+		},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			tc.mockCalls()
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(tc.method, tc.target, nil)
+
+			suite.router.ServeHTTP(w, r)
+
+			suite.Equal(tc.expectedCode, w.Code)
+
+			if tc.expectedDescription == "" {
+				return
+			}
+
+			// the quarantined articles are served sanitised like the stored ones
+			var response model.QuarantineResponse
+			if strings.HasPrefix(tc.target, "/quarantine/") {
+				response.Items = make([]model.QuarantinedItem, 1)
+				suite.NoError(json.NewDecoder(w.Body).Decode(&response.Items[0]))
+			} else {
+				suite.NoError(json.NewDecoder(w.Body).Decode(&response))
 			}
+
+			suite.Require().Len(response.Items, 1)
+			suite.Equal(tc.expectedDescription, response.Items[0].Article.Descriptiopn)
+		})
+	}
+}
+
+func (suite *TestSuite) TestLoadDocument() {
+	testCases := []struct {
+		name         string
+		target       string
+		mockCalls    func()
+		expectedCode int
+	}{
+		{
+			name:         "LoadDocumentWithoutSource",
+			target:       "/load",
+			mockCalls:    func() {},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:   "LoadInvalidDocument",
+			target: "/load?sourceId=test%20id",
+			mockCalls: func() {
+				suite.serviceMock.EXPECT().LoadDocument(gomock.Any(), gomock.Any(), gomock.Any()).Return(model.LoadReport{}, ErrInvalidFeed)
+			},
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:   "LoadDocumentSuccess",
+			target: "/load?feedUrl=https://partner.example.com/rss.xml&provider=partner&dryRun=true",
+			mockCalls: func() {
+				ur := model.UploadRequest{FeedURL: "https://partner.example.com/rss.xml", Provider: "partner", DryRun: true, ContentType: "application/rss+xml"}
+				suite.serviceMock.EXPECT().LoadDocument(gomock.Any(), ur, gomock.Any()).DoAndReturn(func(_ context.Context, _ model.UploadRequest, document io.Reader) (model.LoadReport, error) {
+					body, err := io.ReadAll(document)
+					suite.NoError(err)
+					suite.Equal("<rss></rss>", string(body))
+
+					return suite.report, nil
+				})
+			},
+			expectedCode: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			tc.mockCalls()
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, tc.target, strings.NewReader("<rss></rss>"))
+			r.Header.Set("Content-Type", "application/rss+xml")
+
+			suite.router.ServeHTTP(w, r)
+
+			suite.Equal(tc.expectedCode, w.Code)
+		})
+	}
+}
+
+func (suite *TestSuite) TestSnapshots() {
+	snapshot := model.Snapshot{ID: "test id", Header: map[string][]string{"Content-Type": {"application/rss+xml"}}}
+
+	testCases := []struct {
+		name                string
+		method              string
+		target              string
+		mockCalls           func()
+		expectedCode        int
+		expectedContentType string
+		expectedBody        string
+	}{
+		{
+			name:         "FindSnapshotsInvalidLimit",
+			method:       http.MethodGet,
+			target:       "/snapshots?limit=5000",
+			mockCalls:    func() {},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:   "FindSnapshotsSuccess",
+			method: http.MethodGet,
+			target: "/snapshots?sourceId=test&limit=10",
+			mockCalls: func() {
+				sr := model.SnapshotRequest{SourceID: "test", Limit: 10}
+				suite.serviceMock.EXPECT().FindSnapshots(gomock.Any(), sr).Return(model.SnapshotResponse{Criteria: sr}, nil)
+			},
+			expectedCode:        http.StatusOK,
+			expectedContentType: "application/json",
+		},
+		{
+			name:   "FindSnapshotNotFound",
+			method: http.MethodGet,
+			target: "/snapshots/test%20id",
+			mockCalls: func() {
+				suite.serviceMock.EXPECT().FindSnapshot(gomock.Any(), "test id").Return(model.Snapshot{}, nil, ErrNotFound)
+			},
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:   "FindSnapshotBodySuccess",
+			method: http.MethodGet,
+			target: "/snapshots/test%20id/body",
+			mockCalls: func() {
+				suite.serviceMock.EXPECT().FindSnapshot(gomock.Any(), "test id").Return(snapshot, []byte("<rss></rss>"), nil)
+			},
+			expectedCode:        http.StatusOK,
+			expectedContentType: "application/rss+xml",
+			expectedBody:        "<rss></rss>",
+		},
+		{
+			name:   "ReplaySnapshotInvalidFeed",
+			method: http.MethodPost,
+			target: "/snapshots/test%20id/replay",
+			mockCalls: func() {
+				suite.serviceMock.EXPECT().ReplaySnapshot(gomock.Any(), "test id", false).Return(model.LoadReport{}, ErrInvalidFeed)
+			},
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:   "ReplaySnapshotDryRun",
+			method: http.MethodPost,
+			target: "/snapshots/test%20id/replay?dryRun=true",
+			mockCalls: func() {
+				suite.serviceMock.EXPECT().ReplaySnapshot(gomock.Any(), "test id", true).Return(suite.report, nil)
+			},
+			expectedCode:        http.StatusOK,
+			expectedContentType: "application/json",
+		},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			tc.mockCalls()
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(tc.method, tc.target, nil)
+
+			suite.router.ServeHTTP(w, r)
+
+			suite.Equal(tc.expectedCode, w.Code)
+
+			if tc.expectedContentType != "" {
+				suite.Equal(tc.expectedContentType, w.Header().Get("Content-Type"))
+			}
+
+			if tc.expectedBody != "" {
+				suite.Equal(tc.expectedBody, w.Body.String())
+			}
+		})
+	}
+}
+
+func (suite *TestSuite) TestLoadJobs() {
+	job := model.Job{ID: "test id", Status: model.JobQueued}
+
+	testCases := []struct {
+		name             string
+		method           string
+		target           string
+		mockCalls        func()
+		expectedCode     int
+		expectedLocation string
+	}{
+		{
+			name:   "CreateLoadJobInvalidFeed",
+			method: http.MethodPost,
+			target: "/loads?feedUrl=ftp://example.com/rss.xml",
+			mockCalls: func() {
+				suite.serviceMock.EXPECT().CreateLoadJob(gomock.Any(), model.LoadRequest{FeedURL: "ftp://example.com/rss.xml"}).Return(model.Job{}, ErrInvalidFeed)
+			},
+			expectedCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:   "CreateLoadJobSuccess",
+			method: http.MethodPost,
+			target: "/loads",
+			mockCalls: func() {
+				suite.serviceMock.EXPECT().CreateLoadJob(gomock.Any(), model.LoadRequest{}).Return(job, nil)
+			},
+			expectedCode:     http.StatusAccepted,
+			expectedLocation: "/loads/test%20id",
+		},
+		{
+			name:         "FindLoadJobsInvalidStatus",
+			method:       http.MethodGet,
+			target:       "/loads?status=done",
+			mockCalls:    func() {},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:   "FindLoadJobsSuccess",
+			method: http.MethodGet,
+			target: "/loads?status=failed&limit=10",
+			mockCalls: func() {
+				jr := model.JobRequest{Status: model.JobFailed, Limit: 10}
+				suite.serviceMock.EXPECT().FindLoadJobs(gomock.Any(), jr).Return(model.JobResponse{Criteria: jr}, nil)
+			},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:   "FindLoadJobNotFound",
+			method: http.MethodGet,
+			target: "/loads/test%20id",
+			mockCalls: func() {
+				suite.serviceMock.EXPECT().FindLoadJob(gomock.Any(), "test id").Return(model.Job{}, ErrNotFound)
+			},
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:   "FindLoadJobSuccess",
+			method: http.MethodGet,
+			target: "/loads/test%20id",
+			mockCalls: func() {
+				suite.serviceMock.EXPECT().FindLoadJob(gomock.Any(), "test id").Return(job, nil)
+			},
+			expectedCode: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			tc.mockCalls()
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(tc.method, tc.target, nil)
+
+			suite.router.ServeHTTP(w, r)
+
+			suite.Equal(tc.expectedCode, w.Code)
+			suite.Equal(tc.expectedLocation, w.Header().Get("Location"))
+		})
+	}
+}
+
+func (suite *TestSuite) TestFindArticleByID() {
+	article := suite.article
+	article.Descriptiopn = `<p>test <script>alert(1)</script>description</p>`
+
+	testCases := []struct {
+		name                string
+		given               string
+		mockCalls           func()
+		expectedCode        int
+		expectedDescription string
+	}{
+		{
+			name: "FindArticleByIDNotFound",
+			mockCalls: func() {
+				suite.serviceMock.EXPECT().FindArticleByID(gomock.Any(), article.ID).Return(model.Article{}, ErrNotFound)
+			},
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:         "FindArticleByIDInvalidFormat",
+			given:        "?format=markdown",
+			mockCalls:    func() {},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name: "FindArticleByIDHTML",
+			mockCalls: func() {
+				suite.serviceMock.EXPECT().FindArticleByID(gomock.Any(), article.ID).Return(article, nil)
+			},
+			expectedCode:        http.StatusOK,
+			expectedDescription: "<p>test description</p>",
+		},
+		{
+			name:  "FindArticleByIDText",
+			given: "?format=text",
+			mockCalls: func() {
+				suite.serviceMock.EXPECT().FindArticleByID(gomock.Any(), article.ID).Return(article, nil)
+			},
+			expectedCode:        http.StatusOK,
+			expectedDescription: "test description",
+		},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			tc.mockCalls()
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/articles/"+url.PathEscape(article.ID)+tc.given, nil)
+
+			suite.router.ServeHTTP(w, r)
+
+			suite.Equal(tc.expectedCode, w.Code)
+
+			if tc.expectedDescription != "" {
+				var response model.Article
+				suite.NoError(json.NewDecoder(w.Body).Decode(&response))
+				suite.Equal(tc.expectedDescription, response.Descriptiopn)
+			}
+		})
+	}
+}
+
+func (suite *TestSuite) TestFindRevisions() {
+	id := "https://www.bbc.co.uk/news/uk-62874346"
+
+	testCases := []struct {
+		name         string
+		mockCalls    func()
+		expectedCode int
+	}{
+		{
+			name: "FindRevisionsNotFound",
+			mockCalls: func() {
+				suite.serviceMock.EXPECT().FindRevisions(gomock.Any(), id).Return(nil, ErrNotFound)
+			},
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name: "FindRevisionsSuccess",
+			mockCalls: func() {
+				suite.serviceMock.EXPECT().FindRevisions(gomock.Any(), id).Return([]model.RevisionDiff{{From: 0, To: 1}}, nil)
+			},
+			expectedCode: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			tc.mockCalls()
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/articles/"+url.PathEscape(id)+"/revisions", nil)
+
+			suite.router.ServeHTTP(w, r)
+
+			suite.Equal(tc.expectedCode, w.Code)
+		})
+	}
+}
+
+func (suite *TestSuite) TestFindStory() {
+	testCases := []struct {
+		name         string
+		mockCalls    func()
+		expectedCode int
+	}{
+		{
+			name: "FindStoryNotFound",
+			mockCalls: func() {
+				suite.serviceMock.EXPECT().FindStory(gomock.Any(), "test id").Return(model.Story{}, ErrNotFound)
+			},
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name: "FindStorySuccess",
+			mockCalls: func() {
+				suite.serviceMock.EXPECT().FindStory(gomock.Any(), "test id").Return(model.Story{
+					ID:        "test id",
+					Title:     suite.article.Title,
+					Providers: []string{model.ProviderBBC},
+					Articles:  []model.Article{suite.article},
+				}, nil)
+			},
+			expectedCode: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			tc.mockCalls()
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/stories/"+url.PathEscape("test id"), nil)
+
+			suite.router.ServeHTTP(w, r)
+
+			suite.Equal(tc.expectedCode, w.Code)
 		})
 	}
 }
@@ -0,0 +1,13 @@
+package news
+
+import "errors"
+
+var (
+	ErrNotFound       = errors.New("not found")
+	ErrInvalidRequest = errors.New("invalid request")
+	ErrAlreadyExists  = errors.New("already exists")
+	ErrInvalidFeed    = errors.New("invalid feed")
+	ErrCircuitOpen    = errors.New("circuit open")
+	ErrHostBusy       = errors.New("timed out waiting for host")
+	ErrRobotsDisallow = errors.New("disallowed by robots.txt")
+)
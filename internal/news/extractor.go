@@ -0,0 +1,344 @@
+package news
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+	"golang.org/x/sync/singleflight"
+)
+
+// minParagraphLength is the min number of characters of a paragraph to be scored
+// shorter ones are usually captions, bylines or links
+const minParagraphLength = 25
+
+// maxRedirects is the max number of redirects followed to a page or a robots.txt
+const maxRedirects = 10
+
+// noiseSelector matches the elements never part of the article body
+const noiseSelector = "script, style, noscript, iframe, form, nav, header, footer, aside, figure figcaption, button, svg"
+
+// extractor fetches article pages and extracts their main text and lead image
+// with a readability-style algorithm: paragraphs are scored by their length and commas
+// and the element holding the highest score is considered the article body
+// pages disallowed by the robots.txt of their host aren't fetched
+type extractor struct {
+	client  *http.Client
+	config  ExtractorConfig
+	limiter *hostLimiter
+
+	mu      sync.Mutex
+	robots  map[string]robots
+	lookups singleflight.Group
+}
+
+// extraction is the outcome of extracting an article page
+type extraction struct {
+	content   string
+	leadImage string
+	wordCount int
+}
+
+// newExtractor - constructor
+func newExtractor(config ExtractorConfig, limiter *hostLimiter) *extractor {
+	return &extractor{
+		client:  &http.Client{CheckRedirect: noRedirect},
+		config:  config,
+		limiter: limiter,
+		robots:  make(map[string]robots),
+	}
+}
+
+// extract fetches the page and extracts its main text and lead image
+// redirects are followed one by one so every page goes through the robots.txt
+// and the limits of its own host, links are often tracking urls redirecting to another host
+func (e *extractor) extract(ctx context.Context, link string) (extraction, error) {
+	ctx, cancel := context.WithTimeout(ctx, e.config.Timeout)
+	defer cancel()
+
+	page, err := url.Parse(link)
+	if err != nil {
+		return extraction{}, err
+	}
+
+	for range maxRedirects + 1 {
+		result, next, err := e.extractPage(ctx, page)
+		if err != nil || next == nil {
+			return result, err
+		}
+
+		page = next
+	}
+
+	return extraction{}, fmt.Errorf("%s: stopped after %d redirects", link, maxRedirects)
+}
+
+// extractPage fetches and extracts a single page if the robots.txt of its host allows it
+// it returns the url redirected to instead, if any
+func (e *extractor) extractPage(ctx context.Context, page *url.URL) (extraction, *url.URL, error) {
+	if page.Scheme != "http" && page.Scheme != "https" {
+		return extraction{}, nil, fmt.Errorf("unsupported url %s", page)
+	}
+
+	rules, err := e.robotsFor(ctx, page)
+	if err != nil {
+		return extraction{}, nil, err
+	}
+
+	if !rules.allowed(page.RequestURI()) {
+		return extraction{}, nil, fmt.Errorf("%w: %s", ErrRobotsDisallow, page)
+	}
+
+	resp, err := e.get(ctx, page, "text/html")
+	if err != nil {
+		return extraction{}, nil, err
+	}
+	defer resp.Body.Close()
+
+	if next, err := redirectLocation(resp); err != nil || next != nil {
+		return extraction{}, next, err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return extraction{}, nil, statusError{StatusCode: resp.StatusCode, Status: resp.Status}
+	}
+
+	if ct := resp.Header.Get("Content-Type"); ct != "" && !strings.Contains(ct, "html") {
+		return extraction{}, nil, fmt.Errorf("unexpected content type %s", ct)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, e.config.MaxBytes+1))
+	if err != nil {
+		return extraction{}, nil, err
+	}
+
+	if int64(len(body)) > e.config.MaxBytes {
+		return extraction{}, nil, fmt.Errorf("page is larger than %d bytes", e.config.MaxBytes)
+	}
+
+	// pages in another charset are converted like the feeds
+	body, err = toUTF8(body, resp.Header.Get("Content-Type"))
+	if err != nil {
+		return extraction{}, nil, err
+	}
+
+	result, err := extractDocument(bytes.NewReader(body), page)
+
+	return result, nil, err
+}
+
+// get sends a GET request once its turn has come for the host
+// the turn is held until the response body is closed
+func (e *extractor) get(ctx context.Context, u *url.URL, accept string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("User-Agent", userAgent)
+
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+
+	release, err := e.limiter.waitCrawl(ctx, u.Host)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		release()
+		return nil, err
+	}
+
+	resp.Body = &releasingBody{ReadCloser: resp.Body, release: release}
+
+	return resp, nil
+}
+
+// releasingBody releases the turn of the host once the response body is closed
+type releasingBody struct {
+	io.ReadCloser
+	once    sync.Once
+	release func()
+}
+
+func (b *releasingBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.release)
+
+	return err
+}
+
+// noRedirect makes the client return redirects rather than follow them
+func noRedirect(*http.Request, []*http.Request) error {
+	return http.ErrUseLastResponse
+}
+
+// redirectLocation returns the url a redirect response points to, nil if it isn't a redirect
+func redirectLocation(resp *http.Response) (*url.URL, error) {
+	switch resp.StatusCode {
+	case http.StatusMovedPermanently, http.StatusFound, http.StatusSeeOther,
+		http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+	default:
+		return nil, nil
+	}
+
+	location, err := resp.Location()
+	if err != nil {
+		return nil, fmt.Errorf("redirect of %s: %w", resp.Request.URL, err)
+	}
+
+	return location, nil
+}
+
+// extractDocument extracts the main text and lead image of an html document
+func extractDocument(r io.Reader, base *url.URL) (extraction, error) {
+	doc, err := goquery.NewDocumentFromReader(r)
+	if err != nil {
+		return extraction{}, err
+	}
+
+	// the lead image is picked before the noise is removed as it's often in the header
+	leadImage := leadImage(doc, base)
+
+	doc.Find(noiseSelector).Remove()
+
+	body := bestCandidate(doc)
+	if body == nil {
+		return extraction{leadImage: leadImage}, nil
+	}
+
+	paragraphs := make([]string, 0)
+
+	body.Find("p, h2, h3, li, blockquote").Each(func(_ int, s *goquery.Selection) {
+		// nested elements are already part of their parent text
+		if s.ParentsFiltered("p, li, blockquote").Length() > 0 {
+			return
+		}
+
+		if text := strings.Join(strings.Fields(s.Text()), " "); text != "" {
+			paragraphs = append(paragraphs, text)
+		}
+	})
+
+	content := strings.Join(paragraphs, "\n\n")
+
+	return extraction{
+		content:   content,
+		leadImage: leadImage,
+		wordCount: len(strings.Fields(content)),
+	}, nil
+}
+
+// bestCandidate returns the element with the highest score
+// every paragraph adds its score to its parent and half of it to its grandparent
+func bestCandidate(doc *goquery.Document) *goquery.Selection {
+	var (
+		nodes  = make([]*goquery.Selection, 0)
+		scores = make(map[*html.Node]float64)
+	)
+
+	add := func(s *goquery.Selection, score float64) {
+		if s.Length() == 0 {
+			return
+		}
+
+		node := s.Get(0)
+		if _, ok := scores[node]; !ok {
+			nodes = append(nodes, s)
+		}
+
+		scores[node] += score
+	}
+
+	doc.Find("p").Each(func(_ int, p *goquery.Selection) {
+		text := strings.TrimSpace(p.Text())
+		if len(text) < minParagraphLength {
+			return
+		}
+
+		score := 1 + float64(strings.Count(text, ",")) + math.Min(float64(len(text))/100, 3)
+
+		add(p.Parent(), score)
+		add(p.Parent().Parent(), score/2)
+	})
+
+	var (
+		best      *goquery.Selection
+		bestScore float64
+	)
+
+	for _, node := range nodes {
+		if score := scores[node.Get(0)] * (1 - linkDensity(node)); score > bestScore {
+			best, bestScore = node, score
+		}
+	}
+
+	return best
+}
+
+// linkDensity returns the ratio of the text of an element within links
+func linkDensity(s *goquery.Selection) float64 {
+	length := len(s.Text())
+	if length == 0 {
+		return 0
+	}
+
+	links := 0
+	s.Find("a").Each(func(_ int, a *goquery.Selection) {
+		links += len(a.Text())
+	})
+
+	return float64(links) / float64(length)
+}
+
+// leadImage returns the absolute url of the og:image or twitter:image of the page
+// or of the first image of the article element
+func leadImage(doc *goquery.Document, base *url.URL) string {
+	candidates := []string{
+		doc.Find(`meta[property="og:image"]`).AttrOr("content", ""),
+		doc.Find(`meta[name="twitter:image"]`).AttrOr("content", ""),
+		doc.Find("article img").First().AttrOr("src", ""),
+	}
+
+	for _, candidate := range candidates {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "" {
+			continue
+		}
+
+		u, err := url.Parse(candidate)
+		if err != nil {
+			continue
+		}
+
+		if base != nil {
+			u = base.ResolveReference(u)
+		}
+
+		if u.Scheme == "http" || u.Scheme == "https" {
+			return u.String()
+		}
+	}
+
+	return ""
+}
+
+// readingTime returns the reading time in minutes, rounded up
+func readingTime(wordCount, wordsPerMinute int) int {
+	if wordCount == 0 || wordsPerMinute <= 0 {
+		return 0
+	}
+
+	return (wordCount + wordsPerMinute - 1) / wordsPerMinute
+}
@@ -0,0 +1,347 @@
+package news
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type ExtractorTestSuite struct {
+	suite.Suite
+	server    *httptest.Server
+	extractor *extractor
+}
+
+func (suite *ExtractorTestSuite) SetupSuite() {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /article.html", func(w http.ResponseWriter, r *http.Request) {
+		http.ServeFile(w, r, "testdata/article.html")
+	})
+	mux.HandleFunc("GET /feed.xml", func(w http.ResponseWriter, r *http.Request) {
+		http.ServeFile(w, r, "testdata/sky_technology.xml")
+	})
+	mux.HandleFunc("GET /large.html", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte("<p>" + strings.Repeat("a", 2<<10) + "</p>"))
+	})
+
+	mux.HandleFunc("GET /latin1.html", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=iso-8859-1")
+		_, _ = w.Write([]byte("<html><body><article><p>The caf\xe9 on the high street, open since 1952, has closed its doors for good.</p></article></body></html>"))
+	})
+	mux.HandleFunc("GET /robots.txt", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("User-agent: *\nDisallow: /private/\nAllow: /private/article.html\n"))
+	})
+	mux.HandleFunc("GET /private/", func(w http.ResponseWriter, r *http.Request) {
+		http.ServeFile(w, r, "testdata/article.html")
+	})
+
+	suite.server = httptest.NewServer(mux)
+}
+
+func (suite *ExtractorTestSuite) TearDownSuite() {
+	suite.server.Close()
+}
+
+func (suite *ExtractorTestSuite) SetupTest() {
+	suite.extractor = newExtractor(ExtractorConfig{Timeout: time.Second, RobotsTTL: time.Hour}, newHostLimiter(0, 0))
+}
+
+func (suite *ExtractorTestSuite) TestExtract() {
+	testCases := []struct {
+		name              string
+		given             string
+		maxBytes          int64
+		expectedErr       bool
+		expectedLeadImage string
+		expectedWordCount int
+	}{
+		{
+			name:              "ExtractSuccess",
+			given:             "/article.html",
+			maxBytes:          1 << 20,
+			expectedLeadImage: suite.server.URL + "/images/apple-eu.jpg",
+			expectedWordCount: 104,
+		},
+		{
+			name:        "ExtractNotHTML",
+			given:       "/feed.xml",
+			maxBytes:    1 << 20,
+			expectedErr: true,
+		},
+		{
+			name:        "ExtractNotFound",
+			given:       "/missing.html",
+			maxBytes:    1 << 20,
+			expectedErr: true,
+		},
+		{
+			name:        "ExtractTooLarge",
+			given:       "/large.html",
+			maxBytes:    1 << 10,
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			suite.extractor.config.MaxBytes = tc.maxBytes
+
+			result, err := suite.extractor.extract(context.Background(), suite.server.URL+tc.given)
+
+			if tc.expectedErr {
+				suite.Error(err)
+				return
+			}
+
+			suite.NoError(err)
+			suite.Equal(tc.expectedLeadImage, result.leadImage)
+			suite.Equal(tc.expectedWordCount, result.wordCount)
+
+			// the article body without the navigation, captions and related stories
+			suite.True(strings.HasPrefix(result.content, "Apple has been fined €1.8bn"))
+			suite.Contains(result.content, "\n\nAppeal planned\n\n")
+			suite.NotContains(result.content, "Storm Kathleen")
+			suite.NotContains(result.content, "By Tech Reporter")
+			suite.NotContains(result.content, "Copyright")
+		})
+	}
+}
+
+func (suite *ExtractorTestSuite) TestExtractCharset() {
+	suite.extractor.config.MaxBytes = 1 << 20
+
+	result, err := suite.extractor.extract(context.Background(), suite.server.URL+"/latin1.html")
+	suite.NoError(err)
+	suite.Equal("The café on the high street, open since 1952, has closed its doors for good.", result.content)
+}
+
+func (suite *ExtractorTestSuite) TestExtractRobots() {
+	suite.extractor.config.MaxBytes = 1 << 20
+
+	_, err := suite.extractor.extract(context.Background(), suite.server.URL+"/private/other.html")
+	suite.ErrorIs(err, ErrRobotsDisallow)
+
+	result, err := suite.extractor.extract(context.Background(), suite.server.URL+"/private/article.html")
+	suite.NoError(err)
+	suite.Equal(104, result.wordCount)
+}
+
+func (suite *ExtractorTestSuite) TestExtractRedirects() {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /allowed", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, suite.server.URL+"/private/article.html", http.StatusFound)
+	})
+	mux.HandleFunc("GET /disallowed", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, suite.server.URL+"/private/other.html", http.StatusMovedPermanently)
+	})
+	mux.HandleFunc("GET /loop", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/loop", http.StatusFound)
+	})
+
+	redirector := httptest.NewServer(mux)
+	defer redirector.Close()
+
+	target, err := url.Parse(suite.server.URL)
+	suite.Require().NoError(err)
+
+	testCases := []struct {
+		name              string
+		given             string
+		expectedErr       error
+		expectedWordCount int
+	}{
+		{
+			name:              "RedirectAllowed",
+			given:             "/allowed",
+			expectedWordCount: 104,
+		},
+		{
+			// the robots.txt of the host redirected to applies
+			name:        "RedirectDisallowed",
+			given:       "/disallowed",
+			expectedErr: ErrRobotsDisallow,
+		},
+		{
+			name:        "RedirectLoop",
+			given:       "/loop",
+			expectedErr: errors.New("stopped after 10 redirects"),
+		},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			suite.extractor.config.MaxBytes = 1 << 20
+
+			result, err := suite.extractor.extract(context.Background(), redirector.URL+tc.given)
+
+			switch {
+			case errors.Is(tc.expectedErr, ErrRobotsDisallow):
+				suite.ErrorIs(err, tc.expectedErr)
+			case tc.expectedErr != nil:
+				suite.ErrorContains(err, tc.expectedErr.Error())
+			default:
+				suite.NoError(err)
+				suite.Equal(tc.expectedWordCount, result.wordCount)
+				suite.Equal(0, suite.extractor.limiter.status(target.Host).Active)
+			}
+		})
+	}
+
+	// every hop has been checked against the robots.txt of its host
+	suite.Contains(suite.extractor.robots, target.Host)
+}
+
+func (suite *ExtractorTestSuite) TestRobotsLookups() {
+	testCases := []struct {
+		name         string
+		robotsStatus int
+		expectedErr  bool
+	}{
+		{
+			name:         "RobotsFound",
+			robotsStatus: http.StatusOK,
+		},
+		{
+			// the page isn't fetched until the robots.txt can be read
+			name:         "RobotsUnavailable",
+			robotsStatus: http.StatusServiceUnavailable,
+			expectedErr:  true,
+		},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			var lookups, pages int32
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/robots.txt" {
+					atomic.AddInt32(&lookups, 1)
+					time.Sleep(20 * time.Millisecond)
+					w.WriteHeader(tc.robotsStatus)
+
+					return
+				}
+
+				atomic.AddInt32(&pages, 1)
+				http.ServeFile(w, r, "testdata/article.html")
+			}))
+			defer server.Close()
+
+			suite.extractor.config.MaxBytes = 1 << 20
+
+			var wg sync.WaitGroup
+
+			errs := make(chan error, 5)
+
+			for range 5 {
+				wg.Add(1)
+
+				go func() {
+					defer wg.Done()
+
+					_, err := suite.extractor.extract(context.Background(), server.URL+"/article.html")
+					errs <- err
+				}()
+			}
+
+			wg.Wait()
+			close(errs)
+
+			for err := range errs {
+				if tc.expectedErr {
+					suite.Error(err)
+				} else {
+					suite.NoError(err)
+				}
+			}
+
+			// the next extraction uses the cached robots.txt, even if it couldn't be fetched
+			_, err := suite.extractor.extract(context.Background(), server.URL+"/article.html")
+			suite.Equal(tc.expectedErr, err != nil)
+
+			suite.Equal(int32(1), atomic.LoadInt32(&lookups))
+
+			if tc.expectedErr {
+				suite.Equal(int32(0), atomic.LoadInt32(&pages))
+			}
+		})
+	}
+}
+
+func (suite *ExtractorTestSuite) TestParseRobots() {
+	testCases := []struct {
+		name               string
+		given              string
+		expectedAllowed    []string
+		expectedDisallowed []string
+		expectedCrawlDelay time.Duration
+	}{
+		{
+			name:            "ParseEmpty",
+			given:           "",
+			expectedAllowed: []string{"/", "/news/article.html"},
+		},
+		{
+			name:               "ParseWildcardGroup",
+			given:              "User-agent: *\nDisallow: /search\nDisallow:\n",
+			expectedAllowed:    []string{"/", "/news/search"},
+			expectedDisallowed: []string{"/search", "/search?q=news"},
+		},
+		{
+			name: "ParseOwnGroup",
+			given: "User-agent: *\nDisallow: /\n\n" +
+				"User-agent: Go-News-Feed\nUser-agent: other-bot\nDisallow: /private\nCrawl-delay: 2.5\n",
+			expectedAllowed:    []string{"/", "/news"},
+			expectedDisallowed: []string{"/private/article.html"},
+			expectedCrawlDelay: 2500 * time.Millisecond,
+		},
+		{
+			name:               "ParseLongestMatch",
+			given:              "User-agent: *\nDisallow: /news/\nAllow: /news/uk/\nAllow: /news/$\n",
+			expectedAllowed:    []string{"/news/", "/news/uk/article.html"},
+			expectedDisallowed: []string{"/news/world/article.html"},
+		},
+		{
+			name:               "ParseWildcards",
+			given:              "# comment\nUser-agent: *\nDisallow: /*.pdf$ # documents\nDisallow: /*/amp\n",
+			expectedAllowed:    []string{"/report.pdf?download=1", "/amp"},
+			expectedDisallowed: []string{"/files/report.pdf", "/news/amp/article.html"},
+		},
+		{
+			name:               "ParseMaxCrawlDelay",
+			given:              "User-agent: *\nCrawl-delay: 3600\n",
+			expectedAllowed:    []string{"/"},
+			expectedCrawlDelay: maxCrawlDelay,
+		},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			rules := parseRobots(strings.NewReader(tc.given))
+
+			for _, path := range tc.expectedAllowed {
+				suite.True(rules.allowed(path), path)
+			}
+
+			for _, path := range tc.expectedDisallowed {
+				suite.False(rules.allowed(path), path)
+			}
+
+			suite.Equal(tc.expectedCrawlDelay, rules.crawlDelay)
+		})
+	}
+}
+
+func TestExtractorTestSuite(t *testing.T) {
+	suite.Run(t, new(ExtractorTestSuite))
+}
@@ -0,0 +1,330 @@
+package news
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+
+	"go-news-feed/pkg/model"
+)
+
+const userAgent = "go-news-feed/1.0"
+
+// fetcher downloads and parses feeds
+// it sends conditional requests based on the ETag and Last-Modified
+// returned by the previous fetch of the source
+// retryable errors are retried with an exponential backoff
+// and hosts failing repeatedly are short-circuited
+// the requests to each host are spaced out and capped by the limiter
+type fetcher struct {
+	client  *http.Client
+	parser  *gofeed.Parser
+	config  FetcherConfig
+	breaker *circuitBreaker
+	limiter *hostLimiter
+	// lookupEnv reads the secrets referenced by the sources
+	lookupEnv func(key string) (string, bool)
+
+	mu      sync.Mutex
+	proxies map[string]*http.Client
+}
+
+// fetchResult is the outcome of fetching a feed
+// feed is nil when the feed hasn't changed since the previous fetch
+type fetchResult struct {
+	feed         *gofeed.Feed
+	notModified  bool
+	etag         string
+	lastModified string
+	fetchedAt    time.Time
+	// body and header are the response as received, to be archived
+	// body is also set when it can't be parsed
+	body   []byte
+	header http.Header
+}
+
+// statusError is returned for non 2xx/304 responses
+type statusError struct {
+	StatusCode int
+	Status     string
+	RetryAfter time.Duration
+}
+
+func (err statusError) Error() string {
+	return fmt.Sprintf("http error: %s", err.Status)
+}
+
+// newFetcher - constructor
+func newFetcher(config FetcherConfig, limiter *hostLimiter) *fetcher {
+	return &fetcher{
+		client:    &http.Client{CheckRedirect: stripSecretHeaders},
+		parser:    gofeed.NewParser(),
+		config:    config,
+		breaker:   newCircuitBreaker(config.BreakerThreshold, config.BreakerCooldown),
+		limiter:   limiter,
+		lookupEnv: os.LookupEnv,
+		proxies:   make(map[string]*http.Client),
+	}
+}
+
+func (f *fetcher) fetch(ctx context.Context, source model.Source) (fetchResult, error) {
+	host := feedHost(source.FeedURL)
+
+	// a source that can't be requested (e.g. a missing secret) says nothing about the host
+	req, err := f.newRequest(ctx, source)
+	if err != nil {
+		return fetchResult{}, err
+	}
+
+	client, err := f.clientFor(source.HTTP)
+	if err != nil {
+		return fetchResult{}, err
+	}
+
+	if !f.breaker.allow(host) {
+		return fetchResult{}, fmt.Errorf("%w for host %s", ErrCircuitOpen, host)
+	}
+
+	var result fetchResult
+
+	for attempt := 0; ; attempt++ {
+		result, err = f.fetchOnce(client, req, source)
+		if err == nil || !isRetryable(err) || attempt >= f.config.Retries {
+			break
+		}
+
+		if werr := sleep(ctx, f.backoff(attempt, err)); werr != nil {
+			err = errors.Join(err, werr)
+			break
+		}
+	}
+
+	// a cancelled request or one still waiting for its turn says nothing about the host
+	if errors.Is(err, context.Canceled) || errors.Is(err, ErrHostBusy) {
+		f.breaker.release(host)
+		return fetchResult{}, err
+	}
+
+	if err != nil && isHostFailure(err) {
+		f.breaker.failure(host)
+		return fetchResult{}, err
+	}
+
+	// a problem of the feed (e.g. a 404 or a body that can't be parsed) doesn't prove the host healthy
+	// so only a feed fetched in full closes the circuit
+	if err != nil {
+		f.breaker.release(host)
+		return result, err
+	}
+
+	f.breaker.success(host)
+
+	return result, nil
+}
+
+// status returns the circuit breaker state of the feed host
+func (f *fetcher) status(feedURL string) model.CircuitStatus {
+	return f.breaker.status(feedHost(feedURL))
+}
+
+// hostStatus returns the request limits of the feed host
+func (f *fetcher) hostStatus(feedURL string) model.HostStatus {
+	return f.limiter.status(feedHost(feedURL))
+}
+
+// fetchOnce sends the request of the feed, without a body it can be sent again on retries
+func (f *fetcher) fetchOnce(client *http.Client, req *http.Request, source model.Source) (fetchResult, error) {
+	if source.Status != nil {
+		if source.Status.ETag != "" {
+			req.Header.Set("If-None-Match", source.Status.ETag)
+		}
+
+		if source.Status.LastModified != "" {
+			req.Header.Set("If-Modified-Since", source.Status.LastModified)
+		}
+	}
+
+	release, err := f.limiter.wait(req.Context(), req.URL.Host)
+	if err != nil {
+		return fetchResult{}, err
+	}
+	defer release()
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fetchResult{}, err
+	}
+	defer resp.Body.Close()
+
+	result := fetchResult{
+		etag:         resp.Header.Get("ETag"),
+		lastModified: resp.Header.Get("Last-Modified"),
+		fetchedAt:    time.Now().UTC(),
+	}
+
+	if resp.StatusCode == http.StatusNotModified {
+		// a 304 often leaves out the validators, the ones sent are still valid
+		if source.Status != nil {
+			result.etag = cmp.Or(result.etag, source.Status.ETag)
+			result.lastModified = cmp.Or(result.lastModified, source.Status.LastModified)
+		}
+
+		result.notModified = true
+
+		return result, nil
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fetchResult{}, statusError{
+			StatusCode: resp.StatusCode,
+			Status:     resp.Status,
+			RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), result.fetchedAt),
+		}
+	}
+
+	result.body, err = f.read(resp.Body)
+	if err != nil {
+		return fetchResult{}, err
+	}
+
+	result.header = resp.Header
+
+	// the body is returned along with the error so it can be looked into
+	result.feed, err = f.parseBody(result.body, resp.Header.Get("Content-Type"))
+	if err != nil {
+		return result, err
+	}
+
+	return result, nil
+}
+
+// parse reads a feed document (RSS, Atom or JSON Feed) of up to the configured max bytes
+func (f *fetcher) parse(r io.Reader, contentType string) (*gofeed.Feed, error) {
+	body, err := f.read(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return f.parseBody(body, contentType)
+}
+
+// read returns the body of a feed of up to the configured max bytes
+func (f *fetcher) read(r io.Reader) ([]byte, error) {
+	body, err := io.ReadAll(io.LimitReader(r, f.config.MaxBytes+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if int64(len(body)) > f.config.MaxBytes {
+		return nil, fmt.Errorf("feed is larger than %d bytes", f.config.MaxBytes)
+	}
+
+	return body, nil
+}
+
+// parseBody converts the charset of the content type to UTF-8 and parses the feed
+func (f *fetcher) parseBody(body []byte, contentType string) (*gofeed.Feed, error) {
+	body, err := toUTF8(body, contentType)
+	if err != nil {
+		return nil, err
+	}
+
+	return f.parser.Parse(bytes.NewReader(body))
+}
+
+// backoff returns the delay before the next attempt
+// the Retry-After of a 429 response takes precedence over the exponential backoff
+func (f *fetcher) backoff(attempt int, err error) time.Duration {
+	delay := f.config.Backoff << attempt
+
+	var se statusError
+	if errors.As(err, &se) && se.RetryAfter > 0 {
+		delay = se.RetryAfter
+	}
+
+	if f.config.MaxBackoff > 0 && (delay > f.config.MaxBackoff || delay < 0) {
+		delay = f.config.MaxBackoff
+	}
+
+	return delay
+}
+
+// isRetryable returns true for timeouts, 5xx and 429 responses
+func isRetryable(err error) bool {
+	var se statusError
+	if errors.As(err, &se) {
+		return se.StatusCode >= 500 || se.StatusCode == http.StatusTooManyRequests
+	}
+
+	var ne net.Error
+
+	return errors.As(err, &ne) && ne.Timeout()
+}
+
+// isHostFailure returns true if the error means the host isn't healthy
+// e.g. a 404 or an unparsable feed is a problem of the feed, not of the host
+func isHostFailure(err error) bool {
+	if isRetryable(err) || errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	var se statusError
+	if errors.As(err, &se) {
+		return false
+	}
+
+	var ne net.Error
+
+	return errors.As(err, &ne)
+}
+
+// parseRetryAfter parses both delay-seconds and http-date formats
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+
+	if date, err := http.ParseTime(value); err == nil && date.After(now) {
+		return date.Sub(now)
+	}
+
+	return 0
+}
+
+// sleep waits for the delay or until ctx is done
+func sleep(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func feedHost(feedURL string) string {
+	u, err := url.Parse(feedURL)
+	if err != nil {
+		return feedURL
+	}
+
+	return u.Host
+}
@@ -0,0 +1,421 @@
+package news
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"go-news-feed/pkg/model"
+)
+
+type FetcherTestSuite struct {
+	suite.Suite
+	fetcher *fetcher
+}
+
+func (suite *FetcherTestSuite) SetupTest() {
+	suite.fetcher = newFetcher(FetcherConfig{
+		MaxBytes:         1 << 20,
+		Retries:          2,
+		Backoff:          time.Millisecond,
+		MaxBackoff:       10 * time.Millisecond,
+		BreakerThreshold: 2,
+		BreakerCooldown:  time.Minute,
+		SecretPrefix:     "FEED_SECRET_",
+	}, newHostLimiter(0, 0))
+
+	secrets := map[string]string{"FEED_SECRET_KEY": "key", "FEED_SECRET_PASSWORD": "password", "MONGO_URI": "mongodb://db"}
+	suite.fetcher.lookupEnv = func(key string) (string, bool) {
+		value, ok := secrets[key]
+		return value, ok
+	}
+}
+
+// serve returns a server replying with the status codes given in order
+// and with the feed fixture once they are exhausted
+func (suite *FetcherTestSuite) serve(calls *int32, codes ...int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		i := int(atomic.AddInt32(calls, 1)) - 1
+		if i < len(codes) {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(codes[i])
+
+			return
+		}
+
+		http.ServeFile(w, r, "testdata/sky_technology.xml")
+	}))
+}
+
+func (suite *FetcherTestSuite) TestFetch() {
+	testCases := []struct {
+		name          string
+		given         []int
+		expectedCalls int32
+		expectedErr   bool
+		expectedState string
+	}{
+		{
+			name:          "FetchSuccess",
+			expectedCalls: 1,
+			expectedState: model.CircuitClosed,
+		},
+		{
+			name:          "FetchRetriesServerErrors",
+			given:         []int{http.StatusServiceUnavailable, http.StatusBadGateway},
+			expectedCalls: 3,
+			expectedState: model.CircuitClosed,
+		},
+		{
+			name:          "FetchRetriesTooManyRequests",
+			given:         []int{http.StatusTooManyRequests},
+			expectedCalls: 2,
+			expectedState: model.CircuitClosed,
+		},
+		{
+			name:          "FetchGivesUpAfterRetries",
+			given:         []int{http.StatusInternalServerError, http.StatusInternalServerError, http.StatusInternalServerError},
+			expectedCalls: 3,
+			expectedErr:   true,
+			expectedState: model.CircuitClosed,
+		},
+		{
+			name:          "FetchDoesNotRetryNotFound",
+			given:         []int{http.StatusNotFound},
+			expectedCalls: 1,
+			expectedErr:   true,
+			expectedState: model.CircuitClosed,
+		},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			var calls int32
+
+			server := suite.serve(&calls, tc.given...)
+			defer server.Close()
+
+			source := model.Source{FeedURL: server.URL}
+
+			result, err := suite.fetcher.fetch(context.Background(), source)
+
+			suite.Equal(tc.expectedCalls, atomic.LoadInt32(&calls))
+			suite.Equal(tc.expectedState, suite.fetcher.status(source.FeedURL).State)
+
+			if tc.expectedErr {
+				suite.Error(err)
+				return
+			}
+
+			suite.NoError(err)
+			suite.Len(result.feed.Items, 2)
+		})
+	}
+}
+
+func (suite *FetcherTestSuite) TestCircuitBreaker() {
+	var calls int32
+
+	server := suite.serve(&calls, http.StatusInternalServerError, http.StatusInternalServerError, http.StatusInternalServerError,
+		http.StatusInternalServerError, http.StatusInternalServerError, http.StatusInternalServerError)
+	defer server.Close()
+
+	now := time.Now()
+	suite.fetcher.breaker.now = func() time.Time { return now }
+	source := model.Source{FeedURL: server.URL}
+
+	// two failed fetches (with their retries) open the circuit
+	for range 2 {
+		_, err := suite.fetcher.fetch(context.Background(), source)
+		suite.Error(err)
+	}
+
+	status := suite.fetcher.status(source.FeedURL)
+	suite.Equal(model.CircuitOpen, status.State)
+	suite.Equal(2, status.Failures)
+	suite.Equal(now.Add(time.Minute), *status.RetryAt)
+
+	// the host isn't called while the circuit is open
+	_, err := suite.fetcher.fetch(context.Background(), source)
+	suite.ErrorIs(err, ErrCircuitOpen)
+	suite.Equal(int32(6), atomic.LoadInt32(&calls))
+
+	// a successful trial once the cooldown has elapsed closes the circuit
+	now = now.Add(time.Minute)
+
+	result, err := suite.fetcher.fetch(context.Background(), source)
+	suite.NoError(err)
+	suite.NotNil(result.feed)
+	suite.Equal(model.CircuitClosed, suite.fetcher.status(source.FeedURL).State)
+}
+
+func (suite *FetcherTestSuite) TestCircuitIgnoresFeedErrors() {
+	var calls int32
+
+	// every 503 is retried twice
+	server := suite.serve(&calls,
+		http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusServiceUnavailable,
+		http.StatusNotFound,
+		http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusServiceUnavailable)
+	defer server.Close()
+
+	source := model.Source{FeedURL: server.URL}
+
+	for range 3 {
+		_, err := suite.fetcher.fetch(context.Background(), source)
+		suite.Error(err)
+	}
+
+	// the 404 in between neither resets nor adds to the failures of the host
+	status := suite.fetcher.status(source.FeedURL)
+	suite.Equal(model.CircuitOpen, status.State)
+	suite.Equal(2, status.Failures)
+	suite.Equal(int32(7), atomic.LoadInt32(&calls))
+}
+
+func (suite *FetcherTestSuite) TestCircuitIgnoresSourceErrors() {
+	var calls int32
+
+	server := suite.serve(&calls, http.StatusInternalServerError, http.StatusInternalServerError, http.StatusInternalServerError)
+	defer server.Close()
+
+	_, err := suite.fetcher.fetch(context.Background(), model.Source{FeedURL: server.URL})
+	suite.Error(err)
+
+	// a source of the host that can't be requested neither resets nor adds to its failures
+	source := model.Source{FeedURL: server.URL, HTTP: &model.HTTPSettings{SecretHeaders: map[string]string{"X-Api-Key": "FEED_SECRET_MISSING"}}}
+
+	for range 2 {
+		_, err := suite.fetcher.fetch(context.Background(), source)
+		suite.ErrorContains(err, "FEED_SECRET_MISSING is not set")
+	}
+
+	status := suite.fetcher.status(source.FeedURL)
+	suite.Equal(model.CircuitClosed, status.State)
+	suite.Equal(1, status.Failures)
+	suite.Equal(int32(3), atomic.LoadInt32(&calls))
+}
+
+func (suite *FetcherTestSuite) TestHostLimits() {
+	var calls int32
+
+	server := suite.serve(&calls)
+	defer server.Close()
+
+	suite.fetcher.limiter = newHostLimiter(time.Minute, 1)
+	source := model.Source{FeedURL: server.URL}
+
+	_, err := suite.fetcher.fetch(context.Background(), source)
+	suite.NoError(err)
+
+	status := suite.fetcher.hostStatus(source.FeedURL)
+	suite.Equal("1m0s", status.MinInterval)
+	suite.Equal(1, status.MaxConcurrent)
+	suite.Equal(0, status.Active)
+	suite.NotNil(status.NextRequestAt)
+
+	// the next fetch of the host has to wait for the interval
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err = suite.fetcher.fetch(ctx, source)
+	suite.ErrorIs(err, ErrHostBusy)
+	suite.Equal(int32(1), atomic.LoadInt32(&calls))
+
+	// waiting isn't a failure of the host
+	suite.Equal(0, suite.fetcher.status(source.FeedURL).Failures)
+	suite.Equal(*status.NextRequestAt, *suite.fetcher.hostStatus(source.FeedURL).NextRequestAt)
+}
+
+func (suite *FetcherTestSuite) TestCrawlDelay() {
+	now := time.Now()
+
+	limiter := newHostLimiter(time.Second, 0)
+	limiter.now = func() time.Time { return now }
+	limiter.setCrawlDelay("example.com", time.Minute)
+
+	release, err := limiter.waitCrawl(context.Background(), "example.com")
+	suite.NoError(err)
+	release()
+
+	// the crawl delay doesn't apply to the feeds, only the interval does
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	now = now.Add(time.Second)
+
+	release, err = limiter.wait(ctx, "example.com")
+	suite.NoError(err)
+	release()
+
+	now = now.Add(time.Second)
+
+	_, err = limiter.waitCrawl(ctx, "example.com")
+	suite.ErrorIs(err, ErrHostBusy)
+
+	status := limiter.status("example.com")
+	suite.Equal("1s", status.MinInterval)
+	suite.Equal("1m0s", status.CrawlDelay)
+}
+
+func (suite *FetcherTestSuite) TestHostConcurrency() {
+	limiter := newHostLimiter(0, 1)
+
+	release, err := limiter.wait(context.Background(), "example.com")
+	suite.NoError(err)
+	suite.Equal(1, limiter.status("example.com").Active)
+
+	// other hosts aren't limited by the requests in flight to example.com
+	releaseOther, err := limiter.wait(context.Background(), "example.org")
+	suite.NoError(err)
+	releaseOther()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err = limiter.wait(ctx, "example.com")
+	suite.ErrorIs(err, ErrHostBusy)
+
+	release()
+	suite.Equal(0, limiter.status("example.com").Active)
+
+	release, err = limiter.wait(context.Background(), "example.com")
+	suite.NoError(err)
+	release()
+}
+
+func (suite *FetcherTestSuite) TestFetchHTTPSettings() {
+	testCases := []struct {
+		name          string
+		given         *model.HTTPSettings
+		expectedCalls int32
+		expectedErr   bool
+	}{
+		{
+			name:          "FetchWithDefaultSettings",
+			expectedCalls: 1,
+		},
+		{
+			name: "FetchWithHeadersAndCredentials",
+			given: &model.HTTPSettings{
+				UserAgent:     "partner-bot/1.0",
+				Headers:       map[string]string{"Accept-Language": "en-GB"},
+				SecretHeaders: map[string]string{"X-Api-Key": "FEED_SECRET_KEY"},
+				BasicAuth:     &model.BasicAuth{Username: "news", PasswordEnv: "FEED_SECRET_PASSWORD"},
+			},
+			expectedCalls: 1,
+		},
+		{
+			name:        "FetchWithMissingSecret",
+			given:       &model.HTTPSettings{SecretHeaders: map[string]string{"X-Api-Key": "FEED_SECRET_MISSING"}},
+			expectedErr: true,
+		},
+		{
+			name:        "FetchWithSecretOutsidePrefix",
+			given:       &model.HTTPSettings{BasicAuth: &model.BasicAuth{Username: "news", PasswordEnv: "MONGO_URI"}},
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			var calls int32
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&calls, 1)
+
+				if tc.given == nil {
+					suite.Equal(userAgent, r.Header.Get("User-Agent"))
+				} else {
+					suite.Equal("partner-bot/1.0", r.Header.Get("User-Agent"))
+					suite.Equal("en-GB", r.Header.Get("Accept-Language"))
+					suite.Equal("key", r.Header.Get("X-Api-Key"))
+
+					username, password, ok := r.BasicAuth()
+					suite.True(ok)
+					suite.Equal("news", username)
+					suite.Equal("password", password)
+				}
+
+				http.ServeFile(w, r, "testdata/sky_technology.xml")
+			}))
+			defer server.Close()
+
+			_, err := suite.fetcher.fetch(context.Background(), model.Source{FeedURL: server.URL, HTTP: tc.given})
+
+			suite.Equal(tc.expectedCalls, atomic.LoadInt32(&calls))
+			suite.Equal(tc.expectedErr, err != nil)
+		})
+	}
+}
+
+func (suite *FetcherTestSuite) TestFetchThroughProxy() {
+	var calls int32
+
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+
+		// the proxy is sent the absolute url of the feed
+		suite.Equal("http://feeds.partner.internal/rss.xml", r.URL.String())
+		suite.Equal("Basic bmV3czpwYXNzd29yZA==", r.Header.Get("Proxy-Authorization"))
+
+		http.ServeFile(w, r, "testdata/sky_technology.xml")
+	}))
+	defer proxy.Close()
+
+	source := model.Source{
+		FeedURL: "http://feeds.partner.internal/rss.xml",
+		HTTP: &model.HTTPSettings{
+			Proxy:     proxy.URL,
+			ProxyAuth: &model.BasicAuth{Username: "news", PasswordEnv: "FEED_SECRET_PASSWORD"},
+		},
+	}
+
+	for range 2 {
+		result, err := suite.fetcher.fetch(context.Background(), source)
+		suite.NoError(err)
+		suite.Len(result.feed.Items, 2)
+	}
+
+	// the client of the proxy is reused
+	suite.Equal(int32(2), atomic.LoadInt32(&calls))
+	suite.Len(suite.fetcher.proxies, 1)
+}
+
+func (suite *FetcherTestSuite) TestRedirectDropsSecretHeaders() {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		suite.Empty(r.Header.Get("X-Api-Key"))
+		suite.Equal("en-GB", r.Header.Get("Accept-Language"))
+
+		http.ServeFile(w, r, "testdata/sky_technology.xml")
+	}))
+	defer target.Close()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		suite.Equal("key", r.Header.Get("X-Api-Key"))
+
+		http.Redirect(w, r, target.URL, http.StatusFound)
+	}))
+	defer server.Close()
+
+	source := model.Source{
+		FeedURL: server.URL,
+		HTTP: &model.HTTPSettings{
+			Headers:       map[string]string{"Accept-Language": "en-GB"},
+			SecretHeaders: map[string]string{"X-Api-Key": "FEED_SECRET_KEY"},
+		},
+	}
+
+	result, err := suite.fetcher.fetch(context.Background(), source)
+	suite.NoError(err)
+	suite.Len(result.feed.Items, 2)
+}
+
+func TestFetcherTestSuite(t *testing.T) {
+	suite.Run(t, new(FetcherTestSuite))
+}
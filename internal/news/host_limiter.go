@@ -0,0 +1,180 @@
+package news
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"go-news-feed/pkg/model"
+)
+
+// hostLimiter spaces out and caps the concurrent requests sent to each host
+// so the many sources of a host (and the pages of their articles) don't get us blocked
+// it's shared by the feed and the extraction fetches
+// the Crawl-delay of the robots.txt of a host only spaces out the extraction fetches
+type hostLimiter struct {
+	// interval is the min time between the start of two requests to the same host
+	interval time.Duration
+	// concurrency is the max number of requests in flight to the same host, unlimited if 0
+	concurrency int
+	now         func() time.Time
+
+	mu    sync.Mutex
+	hosts map[string]*hostState
+}
+
+type hostState struct {
+	// slots bounds the requests in flight, it's nil when unlimited
+	slots  chan struct{}
+	active int
+	// next is the earliest start of the next request
+	next time.Time
+	// nextCrawl is the earliest start of the next extraction fetch
+	nextCrawl time.Time
+	// crawlDelay is the Crawl-delay of the robots.txt of the host
+	crawlDelay time.Duration
+}
+
+// newHostLimiter - constructor
+func newHostLimiter(interval time.Duration, concurrency int) *hostLimiter {
+	return &hostLimiter{
+		interval:    interval,
+		concurrency: concurrency,
+		now:         time.Now,
+		hosts:       make(map[string]*hostState),
+	}
+}
+
+// wait blocks until a feed can be fetched from the host
+// the release func must be called once the response has been read
+func (l *hostLimiter) wait(ctx context.Context, host string) (func(), error) {
+	return l.acquire(ctx, host, false)
+}
+
+// waitCrawl blocks until a page (or a robots.txt) can be fetched from the host
+// it's also spaced out by the Crawl-delay of the host
+// the release func must be called once the response has been read
+func (l *hostLimiter) waitCrawl(ctx context.Context, host string) (func(), error) {
+	return l.acquire(ctx, host, true)
+}
+
+func (l *hostLimiter) acquire(ctx context.Context, host string, crawl bool) (func(), error) {
+	h := l.host(host)
+
+	if h.slots != nil {
+		select {
+		case h.slots <- struct{}{}:
+		case <-ctx.Done():
+			return nil, fmt.Errorf("%w %s: %v", ErrHostBusy, host, ctx.Err())
+		}
+	}
+
+	l.mu.Lock()
+	now := l.now()
+
+	start := now
+	if h.next.After(start) {
+		start = h.next
+	}
+
+	if crawl && h.nextCrawl.After(start) {
+		start = h.nextCrawl
+	}
+
+	previous, previousCrawl := h.next, h.nextCrawl
+
+	next := start.Add(l.interval)
+	h.next = next
+
+	var nextCrawl time.Time
+	if crawl {
+		nextCrawl = start.Add(max(l.interval, h.crawlDelay))
+		h.nextCrawl = nextCrawl
+	}
+
+	h.active++
+	l.mu.Unlock()
+
+	release := func() {
+		l.mu.Lock()
+		h.active--
+		l.mu.Unlock()
+
+		if h.slots != nil {
+			<-h.slots
+		}
+	}
+
+	if err := sleep(ctx, start.Sub(now)); err != nil {
+		// the turn is given back unless a later request has already been scheduled after it
+		l.mu.Lock()
+		if h.next.Equal(next) {
+			h.next = previous
+		}
+
+		if crawl && h.nextCrawl.Equal(nextCrawl) {
+			h.nextCrawl = previousCrawl
+		}
+		l.mu.Unlock()
+
+		release()
+
+		return nil, fmt.Errorf("%w %s: %v", ErrHostBusy, host, err)
+	}
+
+	return release, nil
+}
+
+// setCrawlDelay spaces out the extraction fetches of the host by the delay, if longer than the interval
+func (l *hostLimiter) setCrawlDelay(host string, delay time.Duration) {
+	h := l.host(host)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	h.crawlDelay = delay
+}
+
+// status returns the limits of the host and its requests in flight
+func (l *hostLimiter) status(host string) model.HostStatus {
+	h := l.host(host)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	status := model.HostStatus{
+		Host:          host,
+		MinInterval:   l.interval.String(),
+		MaxConcurrent: l.concurrency,
+		Active:        h.active,
+	}
+
+	if h.crawlDelay > 0 {
+		status.CrawlDelay = h.crawlDelay.String()
+	}
+
+	if h.next.After(l.now()) {
+		next := h.next
+		status.NextRequestAt = &next
+	}
+
+	return status
+}
+
+func (l *hostLimiter) host(host string) *hostState {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	h, ok := l.hosts[host]
+	if !ok {
+		h = &hostState{}
+		if l.concurrency > 0 {
+			h.slots = make(chan struct{}, l.concurrency)
+		}
+
+		l.hosts[host] = h
+	}
+
+	return h
+}
@@ -0,0 +1,208 @@
+package news
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"golang.org/x/net/http/httpguts"
+
+	"go-news-feed/pkg/model"
+)
+
+// credentialHeaders can only be set as secret headers
+// otherwise the credentials would be stored in plain text
+var credentialHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie"}
+
+// secretHeadersKey is the context key of the secret headers of a request
+type secretHeadersKey struct{}
+
+// validateHTTPSettings checks the settings of the requests the validator can't check
+// i.e. the header names, the proxy url and that secrets are referenced rather than stored
+func validateHTTPSettings(settings *model.HTTPSettings, secretPrefix string) error {
+	if settings == nil {
+		return nil
+	}
+
+	for name := range settings.Headers {
+		if !httpguts.ValidHeaderFieldName(name) {
+			return fmt.Errorf("%w: invalid header name %q", ErrInvalidRequest, name)
+		}
+
+		if slices.Contains(credentialHeaders, http.CanonicalHeaderKey(name)) {
+			return fmt.Errorf("%w: %s must be a secret header", ErrInvalidRequest, name)
+		}
+
+		// the user agent of the request is always set from userAgent
+		if http.CanonicalHeaderKey(name) == "User-Agent" {
+			return fmt.Errorf("%w: %s must be set in userAgent", ErrInvalidRequest, name)
+		}
+	}
+
+	secrets := make([]string, 0)
+
+	for name, env := range settings.SecretHeaders {
+		if !httpguts.ValidHeaderFieldName(name) {
+			return fmt.Errorf("%w: invalid header name %q", ErrInvalidRequest, name)
+		}
+
+		secrets = append(secrets, env)
+	}
+
+	for _, auth := range []*model.BasicAuth{settings.BasicAuth, settings.ProxyAuth} {
+		if auth != nil {
+			secrets = append(secrets, auth.PasswordEnv)
+		}
+	}
+
+	for _, env := range secrets {
+		if !strings.HasPrefix(env, secretPrefix) {
+			return fmt.Errorf("%w: secret %s must start with %s", ErrInvalidRequest, env, secretPrefix)
+		}
+	}
+
+	if settings.Proxy == "" {
+		if settings.ProxyAuth != nil {
+			return fmt.Errorf("%w: proxy auth without proxy", ErrInvalidRequest)
+		}
+
+		return nil
+	}
+
+	proxy, err := url.Parse(settings.Proxy)
+	if err != nil || !slices.Contains([]string{"http", "https", "socks5"}, proxy.Scheme) || proxy.Host == "" {
+		return fmt.Errorf("%w: invalid proxy %q", ErrInvalidRequest, settings.Proxy)
+	}
+
+	if proxy.User != nil {
+		return fmt.Errorf("%w: proxy credentials must be set in proxyAuth", ErrInvalidRequest)
+	}
+
+	return nil
+}
+
+// newRequest returns the request of the feed of the source
+// with its user agent, headers and credentials
+func (f *fetcher) newRequest(ctx context.Context, source model.Source) (*http.Request, error) {
+	settings := source.HTTP
+	if settings == nil {
+		settings = &model.HTTPSettings{}
+	}
+
+	// secret headers are dropped if the feed redirects to another host
+	secrets := make([]string, 0, len(settings.SecretHeaders))
+	for name := range settings.SecretHeaders {
+		secrets = append(secrets, name)
+	}
+
+	ctx = context.WithValue(ctx, secretHeadersKey{}, secrets)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, source.FeedURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	for name, value := range settings.Headers {
+		req.Header.Set(name, value)
+	}
+
+	req.Header.Set("User-Agent", userAgent)
+	if settings.UserAgent != "" {
+		req.Header.Set("User-Agent", settings.UserAgent)
+	}
+
+	for name, env := range settings.SecretHeaders {
+		value, err := f.secret(env)
+		if err != nil {
+			return nil, err
+		}
+
+		req.Header.Set(name, value)
+	}
+
+	if settings.BasicAuth != nil {
+		password, err := f.secret(settings.BasicAuth.PasswordEnv)
+		if err != nil {
+			return nil, err
+		}
+
+		req.SetBasicAuth(settings.BasicAuth.Username, password)
+	}
+
+	return req, nil
+}
+
+// clientFor returns the client of the proxy of the source, if any
+// clients are kept by proxy so their connections are reused across fetches
+func (f *fetcher) clientFor(settings *model.HTTPSettings) (*http.Client, error) {
+	if settings == nil || settings.Proxy == "" {
+		return f.client, nil
+	}
+
+	proxy, err := url.Parse(settings.Proxy)
+	if err != nil {
+		return nil, err
+	}
+
+	if settings.ProxyAuth != nil {
+		password, err := f.secret(settings.ProxyAuth.PasswordEnv)
+		if err != nil {
+			return nil, err
+		}
+
+		proxy.User = url.UserPassword(settings.ProxyAuth.Username, password)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	key := proxy.String()
+
+	if client, ok := f.proxies[key]; ok {
+		return client, nil
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = http.ProxyURL(proxy)
+
+	client := &http.Client{Transport: transport, CheckRedirect: stripSecretHeaders}
+	f.proxies[key] = client
+
+	return client, nil
+}
+
+// secret returns the value of the environment variable referenced by a source
+func (f *fetcher) secret(env string) (string, error) {
+	if !strings.HasPrefix(env, f.config.SecretPrefix) {
+		return "", fmt.Errorf("secret %s must start with %s", env, f.config.SecretPrefix)
+	}
+
+	value, ok := f.lookupEnv(env)
+	if !ok || value == "" {
+		return "", fmt.Errorf("secret %s is not set", env)
+	}
+
+	return value, nil
+}
+
+// stripSecretHeaders drops the secret headers of a request redirected to another host
+// like the client does for the Authorization header
+func stripSecretHeaders(req *http.Request, via []*http.Request) error {
+	if len(via) >= 10 {
+		return fmt.Errorf("stopped after %d redirects", len(via))
+	}
+
+	if req.URL.Host == via[0].URL.Host {
+		return nil
+	}
+
+	secrets, _ := req.Context().Value(secretHeadersKey{}).([]string)
+	for _, name := range secrets {
+		req.Header.Del(name)
+	}
+
+	return nil
+}
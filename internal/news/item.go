@@ -0,0 +1,132 @@
+package news
+
+import (
+	"strings"
+
+	"github.com/mmcdole/gofeed"
+	ext "github.com/mmcdole/gofeed/extensions"
+
+	"go-news-feed/pkg/model"
+)
+
+// itemAuthors returns the authors of a feed item
+// e.g. rss author or dc:creator, atom authors and json feed authors
+func itemAuthors(item *gofeed.Item) []model.Author {
+	authors := make([]model.Author, 0, len(item.Authors))
+
+	for _, person := range item.Authors {
+		if person == nil || (person.Name == "" && person.Email == "") {
+			continue
+		}
+
+		authors = append(authors, model.Author{
+			Name:  strings.TrimSpace(person.Name),
+			Email: strings.TrimSpace(person.Email),
+		})
+	}
+
+	if len(authors) == 0 {
+		return nil
+	}
+
+	return authors
+}
+
+// itemTags returns the categories of a feed item without blanks and duplicates
+func itemTags(item *gofeed.Item) []string {
+	var (
+		tags = make([]string, 0, len(item.Categories))
+		seen = make(map[string]bool)
+	)
+
+	for _, category := range item.Categories {
+		category = strings.TrimSpace(category)
+		if category == "" || seen[category] {
+			continue
+		}
+
+		seen[category] = true
+		tags = append(tags, category)
+	}
+
+	if len(tags) == 0 {
+		return nil
+	}
+
+	return tags
+}
+
+// itemImage returns the image of a feed item
+// media:thumbnail isn't mapped by gofeed so it's read from the extensions
+// as well as media:content for atom feeds, then image enclosures are used
+func itemImage(item *gofeed.Item) *model.Image {
+	if item.Image != nil && item.Image.URL != "" {
+		return &model.Image{URL: item.Image.URL, Title: item.Image.Title}
+	}
+
+	if url := mediaImage(item.Extensions); url != "" {
+		return &model.Image{URL: url}
+	}
+
+	for _, enclosure := range item.Enclosures {
+		if enclosure != nil && enclosure.URL != "" && strings.HasPrefix(enclosure.Type, "image/") {
+			return &model.Image{URL: enclosure.URL}
+		}
+	}
+
+	return nil
+}
+
+// mediaImage returns the url of the first media:thumbnail
+// or media:content image of the extensions (media rss)
+func mediaImage(extensions ext.Extensions) string {
+	media, ok := extensions["media"]
+	if !ok {
+		return ""
+	}
+
+	for _, thumbnail := range media["thumbnail"] {
+		if url := thumbnail.Attrs["url"]; url != "" {
+			return url
+		}
+	}
+
+	for _, content := range media["content"] {
+		isImage := strings.HasPrefix(content.Attrs["type"], "image/") || content.Attrs["medium"] == "image"
+		if url := content.Attrs["url"]; url != "" && isImage {
+			return url
+		}
+	}
+
+	// media:content elements can be nested in media:group
+	for _, group := range media["group"] {
+		if url := mediaImage(ext.Extensions{"media": group.Children}); url != "" {
+			return url
+		}
+	}
+
+	return ""
+}
+
+// itemEnclosures returns the media files attached to a feed item
+func itemEnclosures(item *gofeed.Item) []model.Enclosure {
+	enclosures := make([]model.Enclosure, 0, len(item.Enclosures))
+
+	for _, enclosure := range item.Enclosures {
+		if enclosure == nil || enclosure.URL == "" {
+			continue
+		}
+
+		enclosures = append(enclosures, model.Enclosure{
+			URL:    enclosure.URL,
+			Type:   enclosure.Type,
+			Length: enclosure.Length,
+		})
+	}
+
+	if len(enclosures) == 0 {
+		return nil
+	}
+
+	return enclosures
+}
@@ -0,0 +1,85 @@
+package news
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-news-feed/pkg/model"
+)
+
+func TestParseFeedItems(t *testing.T) {
+	testCases := []struct {
+		name     string
+		given    string
+		expected model.Article
+	}{
+		{
+			name:  "RSS",
+			given: "testdata/bbc_uk.xml",
+			expected: model.Article{
+				Authors:     []model.Author{{Name: "Weather Team"}},
+				Tags:        []string{"Weather", "UK"},
+				Image:       &model.Image{URL: "https://ichef.bbci.co.uk/news/240/storm.jpg"},
+				Enclosures:  []model.Enclosure{{URL: "https://downloads.bbc.co.uk/podcasts/storm.mp3", Type: "audio/mpeg", Length: "1048576"}},
+				FeedContent: "<p>Forecasters say the strongest gusts are expected along <b>western coasts</b>.</p>",
+			},
+		},
+		{
+			name:  "Atom",
+			given: "testdata/atom.xml",
+			expected: model.Article{
+				Authors:     []model.Author{{Name: "Jane Reporter", Email: "jane@example.com"}, {Name: "John Reporter"}},
+				Tags:        []string{"transport", "Local news"},
+				Image:       &model.Image{URL: "https://news.example.com/cycle-lanes-large.jpg"},
+				Enclosures:  []model.Enclosure{{URL: "https://news.example.com/cycle-lanes.jpg", Type: "image/jpeg", Length: "20480"}},
+				FeedContent: "<p>The lanes will connect the city centre to the university.</p>",
+			},
+		},
+		{
+			name:  "JSONFeed",
+			given: "testdata/feed.json",
+			expected: model.Article{
+				Authors:     []model.Author{{Name: "Met Desk"}},
+				Tags:        []string{"weather", "summer"},
+				Image:       &model.Image{URL: "https://json.example.com/heatwave.jpg"},
+				Enclosures:  []model.Enclosure{{URL: "https://json.example.com/heatwave.mp3", Type: "audio/mpeg", Length: "0"}},
+				FeedContent: "<p>Temperatures could reach <em>30C</em> in the south east.</p>",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			f, err := os.Open(tc.given)
+			require.NoError(t, err)
+			defer f.Close()
+
+			feed, err := gofeed.NewParser().Parse(f)
+			require.NoError(t, err)
+
+			articles, err := (&service{}).parseFeed(feed, model.Source{}, time.Now())
+			require.NoError(t, err)
+
+			article := articles[0]
+			assert.Equal(t, tc.expected.Authors, article.Authors)
+			assert.Equal(t, tc.expected.Tags, article.Tags)
+			assert.Equal(t, tc.expected.Image, article.Image)
+			assert.Equal(t, tc.expected.Enclosures, article.Enclosures)
+			assert.Equal(t, tc.expected.FeedContent, article.FeedContent)
+		})
+	}
+}
+
+func TestItemWithoutMetadata(t *testing.T) {
+	item := &gofeed.Item{Authors: []*gofeed.Person{{}}, Categories: []string{" "}, Enclosures: []*gofeed.Enclosure{{}}}
+
+	assert.Nil(t, itemAuthors(item))
+	assert.Nil(t, itemTags(item))
+	assert.Nil(t, itemImage(item))
+	assert.Nil(t, itemEnclosures(item))
+}
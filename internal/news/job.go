@@ -0,0 +1,145 @@
+package news
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"go-news-feed/pkg/model"
+)
+
+// jobRunner runs the queued load jobs in the background
+// through the same Service.Load path used by the /load endpoint
+// the jobs it runs are held with a lease it keeps renewing, so instances running side by side
+// only fail the jobs of an instance that has stopped
+type jobRunner struct {
+	service       Service
+	jobRepository JobRepository
+	config        JobConfig
+	now           func() time.Time
+	// owner identifies the instance in the jobs it runs
+	owner string
+
+	// slots bounds the number of jobs run concurrently
+	slots chan struct{}
+	wg    sync.WaitGroup
+}
+
+// newJobRunner - constructor
+func newJobRunner(service Service, jobRepository JobRepository, config JobConfig) *jobRunner {
+	return &jobRunner{
+		service:       service,
+		jobRepository: jobRepository,
+		config:        config,
+		now:           time.Now,
+		owner:         newID(),
+		slots:         make(chan struct{}, max(1, config.Workers)),
+	}
+}
+
+// run claims the queued jobs on every poll until ctx is cancelled
+// it only returns once every running job has finished
+func (r *jobRunner) run(ctx context.Context) {
+	ticker := time.NewTicker(r.config.PollInterval)
+	defer ticker.Stop()
+
+	defer r.wg.Wait()
+
+	for {
+		r.failExpired(ctx)
+		r.runQueued(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// failExpired fails the jobs left running by a stopped instance, they can't be resumed
+func (r *jobRunner) failExpired(ctx context.Context) {
+	n, err := r.jobRepository.FailExpired(ctx, "interrupted, the instance running the job stopped", r.now().UTC())
+	if err != nil {
+		log.Printf("jobs: failed to fail interrupted jobs. err: %v\n", err)
+	} else if n > 0 {
+		log.Printf("jobs: %d interrupted jobs failed\n", n)
+	}
+}
+
+// runQueued starts the queued jobs while a slot is available
+func (r *jobRunner) runQueued(ctx context.Context) {
+	for {
+		select {
+		case r.slots <- struct{}{}:
+		default:
+			return
+		}
+
+		now := r.now().UTC()
+
+		job, err := r.jobRepository.ClaimNext(ctx, r.owner, now, now.Add(r.config.Lease))
+		if err != nil {
+			<-r.slots
+
+			if !errors.Is(err, ErrNotFound) {
+				log.Printf("jobs: failed to claim next job. err: %v\n", err)
+			}
+
+			return
+		}
+
+		r.wg.Add(1)
+
+		go func(job model.Job) {
+			defer r.wg.Done()
+			defer func() { <-r.slots }()
+
+			jobCtx, cancel := context.WithCancel(ctx)
+			defer cancel()
+
+			renewed := make(chan struct{})
+
+			go func(id string) {
+				defer close(renewed)
+				r.renew(jobCtx, cancel, id)
+			}(job.ID)
+
+			if job = r.service.RunLoadJob(jobCtx, job); job.Status == model.JobFailed {
+				log.Printf("jobs: job %s failed. err: %v\n", job.ID, job.Error)
+			}
+
+			cancel()
+			<-renewed
+		}(job)
+	}
+}
+
+// renew extends the lease of the job until ctx is done
+// the job is cancelled if its lease is lost, i.e. it has been failed by another instance
+func (r *jobRunner) renew(ctx context.Context, cancel context.CancelFunc, id string) {
+	ticker := time.NewTicker(r.config.Lease / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		err := r.jobRepository.RenewLease(ctx, id, r.owner, r.now().UTC().Add(r.config.Lease))
+		if errors.Is(err, ErrNotFound) {
+			log.Printf("jobs: lost the lease of job %s, cancelling it\n", id)
+			cancel()
+
+			return
+		}
+
+		if err != nil && ctx.Err() == nil {
+			log.Printf("jobs: failed to renew the lease of job %s. err: %v\n", id, err)
+		}
+	}
+}
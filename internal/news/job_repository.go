@@ -0,0 +1,204 @@
+package news
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"go-news-feed/pkg/model"
+)
+
+// JobRepository - interface
+//
+//go:generate mockgen -source=job_repository.go -destination=job_repository_mock.go --package=news
+type JobRepository interface {
+	Create(ctx context.Context, job model.Job) error
+	FindByID(ctx context.Context, id string) (model.Job, error)
+	Find(ctx context.Context, jr model.JobRequest) (model.JobResponse, error)
+	ClaimNext(ctx context.Context, owner string, startedAt, leaseExpiresAt time.Time) (model.Job, error)
+	RenewLease(ctx context.Context, id, owner string, leaseExpiresAt time.Time) error
+	UpdateProgress(ctx context.Context, job model.Job) error
+	Finish(ctx context.Context, job model.Job) error
+	FailExpired(ctx context.Context, reason string, now time.Time) (int, error)
+}
+
+type jobRepository struct {
+	collection *mongo.Collection
+}
+
+// newJobRepository - constructor
+func newJobRepository(ctx context.Context, db *mongo.Database, config MongoConfig) (JobRepository, error) {
+	collection := db.Collection(config.JobCollection)
+
+	_, err := collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "createdAt", Value: 1}}},
+		{Keys: bson.D{{Key: "createdAt", Value: -1}}},
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "leaseExpiresAt", Value: 1}}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &jobRepository{collection: collection}, nil
+}
+
+func (r jobRepository) Create(ctx context.Context, job model.Job) error {
+	_, err := r.collection.InsertOne(ctx, &job)
+
+	return err
+}
+
+func (r jobRepository) FindByID(ctx context.Context, id string) (model.Job, error) {
+	var job model.Job
+
+	if err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&job); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return model.Job{}, ErrNotFound
+		}
+
+		return model.Job{}, err
+	}
+
+	return job, nil
+}
+
+// Find returns the jobs (with the status, if any), latest first
+func (r jobRepository) Find(ctx context.Context, jr model.JobRequest) (model.JobResponse, error) {
+	filter := bson.M{}
+	if jr.Status != "" {
+		filter["status"] = jr.Status
+	}
+
+	limit := jr.Limit
+	if limit == 0 || limit > maxLimit {
+		limit = maxLimit
+	}
+
+	total, err := r.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return model.JobResponse{}, err
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "createdAt", Value: -1}}).
+		SetSkip(int64(jr.Page * limit)).
+		SetLimit(int64(limit))
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return model.JobResponse{}, err
+	}
+
+	jobs := make([]model.Job, 0)
+	if err := cursor.All(ctx, &jobs); err != nil {
+		return model.JobResponse{}, err
+	}
+
+	return model.JobResponse{Criteria: jr, Jobs: jobs, Total: int(total)}, nil
+}
+
+// ClaimNext marks the oldest queued job as running, held by the owner until the lease expires
+// it returns ErrNotFound when no job is queued
+func (r jobRepository) ClaimNext(ctx context.Context, owner string, startedAt, leaseExpiresAt time.Time) (model.Job, error) {
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "createdAt", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	update := bson.M{"$set": bson.M{
+		"status":         model.JobRunning,
+		"startedAt":      startedAt,
+		"owner":          owner,
+		"leaseExpiresAt": leaseExpiresAt,
+	}}
+
+	var job model.Job
+
+	if err := r.collection.FindOneAndUpdate(ctx, bson.M{"status": model.JobQueued}, update, opts).Decode(&job); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return model.Job{}, ErrNotFound
+		}
+
+		return model.Job{}, err
+	}
+
+	return job, nil
+}
+
+// RenewLease extends the lease of a running job held by the owner
+// it returns ErrNotFound when the job isn't running or is held by another owner
+func (r jobRepository) RenewLease(ctx context.Context, id, owner string, leaseExpiresAt time.Time) error {
+	return r.updateRunning(ctx, id, owner, bson.M{"$set": bson.M{"leaseExpiresAt": leaseExpiresAt}})
+}
+
+// UpdateProgress sets the progress and the report of a running job held by its owner
+// it returns ErrNotFound when the job isn't running or is held by another owner
+func (r jobRepository) UpdateProgress(ctx context.Context, job model.Job) error {
+	return r.updateRunning(ctx, job.ID, job.Owner, bson.M{"$set": bson.M{
+		"progress": job.Progress,
+		"report":   job.Report,
+	}})
+}
+
+// Finish sets the outcome of a running job held by its owner and releases its lease
+// it returns ErrNotFound when the job isn't running or is held by another owner
+func (r jobRepository) Finish(ctx context.Context, job model.Job) error {
+	set := bson.M{
+		"status":     job.Status,
+		"progress":   job.Progress,
+		"finishedAt": job.FinishedAt,
+	}
+
+	if job.Report != nil {
+		set["report"] = job.Report
+	}
+
+	if job.Error != "" {
+		set["error"] = job.Error
+	}
+
+	return r.updateRunning(ctx, job.ID, job.Owner, bson.M{"$set": set, "$unset": bson.M{"leaseExpiresAt": ""}})
+}
+
+func (r jobRepository) updateRunning(ctx context.Context, id, owner string, update bson.M) error {
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": id, "owner": owner, "status": model.JobRunning}, update)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// FailExpired marks the running jobs whose lease has expired as failed
+// as the instance running them has stopped, jobs claimed before leases were introduced included
+// it returns the number of jobs updated
+func (r jobRepository) FailExpired(ctx context.Context, reason string, now time.Time) (int, error) {
+	filter := bson.M{
+		"status": model.JobRunning,
+		"$or": bson.A{
+			bson.M{"leaseExpiresAt": bson.M{"$lt": now}},
+			bson.M{"leaseExpiresAt": bson.M{"$exists": false}},
+		},
+	}
+
+	result, err := r.collection.UpdateMany(ctx, filter, bson.M{
+		"$set": bson.M{
+			"status":     model.JobFailed,
+			"error":      reason,
+			"finishedAt": now,
+		},
+		"$unset": bson.M{"leaseExpiresAt": ""},
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return int(result.ModifiedCount), nil
+}
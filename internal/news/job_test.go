@@ -0,0 +1,162 @@
+package news
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/suite"
+
+	"go-news-feed/pkg/model"
+)
+
+type JobRunnerTestSuite struct {
+	suite.Suite
+	serviceMock       *MockService
+	jobRepositoryMock *MockJobRepository
+	runner            *jobRunner
+	now               time.Time
+}
+
+func (suite *JobRunnerTestSuite) SetupTest() {
+	ctrl := gomock.NewController(suite.T())
+	suite.serviceMock = NewMockService(ctrl)
+	suite.jobRepositoryMock = NewMockJobRepository(ctrl)
+	suite.now = time.Date(2024, 5, 14, 10, 0, 0, 0, time.UTC)
+
+	suite.runner = newJobRunner(suite.serviceMock, suite.jobRepositoryMock, JobConfig{
+		Workers:      2,
+		PollInterval: time.Second,
+		Lease:        time.Minute,
+	})
+	suite.runner.now = func() time.Time { return suite.now }
+	suite.runner.owner = "owner"
+}
+
+func (suite *JobRunnerTestSuite) TestRunQueued() {
+	testCases := []struct {
+		name         string
+		mockCalls    func()
+		expectedRuns int
+	}{
+		{
+			name: "RunUpToWorkers",
+			mockCalls: func() {
+				gomock.InOrder(
+					suite.jobRepositoryMock.EXPECT().ClaimNext(gomock.Any(), "owner", suite.now, suite.now.Add(time.Minute)).Return(model.Job{ID: "1"}, nil),
+					suite.jobRepositoryMock.EXPECT().ClaimNext(gomock.Any(), "owner", suite.now, suite.now.Add(time.Minute)).Return(model.Job{ID: "2"}, nil),
+				)
+			},
+			expectedRuns: 2,
+		},
+		{
+			name: "RunNothingQueued",
+			mockCalls: func() {
+				suite.jobRepositoryMock.EXPECT().ClaimNext(gomock.Any(), "owner", suite.now, suite.now.Add(time.Minute)).Return(model.Job{}, ErrNotFound)
+			},
+		},
+		{
+			name: "RunClaimError",
+			mockCalls: func() {
+				suite.jobRepositoryMock.EXPECT().ClaimNext(gomock.Any(), "owner", suite.now, suite.now.Add(time.Minute)).Return(model.Job{}, errors.New("connection lost"))
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			tc.mockCalls()
+
+			// jobs block until every slot is taken, so the runner can't claim more jobs than workers
+			release := make(chan struct{})
+
+			suite.serviceMock.EXPECT().RunLoadJob(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, job model.Job) model.Job {
+				<-release
+
+				job.Status = model.JobSucceeded
+
+				return job
+			}).Times(tc.expectedRuns)
+
+			suite.runner.runQueued(context.Background())
+			close(release)
+			suite.runner.wg.Wait()
+
+			// every slot is released once the jobs are done
+			suite.Empty(suite.runner.slots)
+		})
+	}
+}
+
+func (suite *JobRunnerTestSuite) TestRunFailsExpiredJobs() {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	suite.jobRepositoryMock.EXPECT().FailExpired(gomock.Any(), gomock.Any(), suite.now).Return(1, nil)
+	suite.jobRepositoryMock.EXPECT().ClaimNext(gomock.Any(), "owner", suite.now, suite.now.Add(time.Minute)).Return(model.Job{}, ErrNotFound)
+
+	suite.runner.run(ctx)
+}
+
+func (suite *JobRunnerTestSuite) TestRenewLease() {
+	suite.runner.config.Lease = 30 * time.Millisecond
+
+	testCases := []struct {
+		name      string
+		renewErr  error
+		cancelled bool
+	}{
+		{
+			name: "RenewLeaseHeld",
+		},
+		{
+			name:      "RenewLeaseLost",
+			renewErr:  ErrNotFound,
+			cancelled: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			// each case has its own mock so the renewals of the previous one can't match
+			jobRepositoryMock := NewMockJobRepository(gomock.NewController(suite.T()))
+			suite.runner.jobRepository = jobRepositoryMock
+
+			var once sync.Once
+
+			renewed := make(chan struct{})
+			jobRepositoryMock.EXPECT().RenewLease(gomock.Any(), "1", "owner", suite.now.Add(30*time.Millisecond)).
+				DoAndReturn(func(context.Context, string, string, time.Time) error {
+					once.Do(func() { close(renewed) })
+					return tc.renewErr
+				}).MinTimes(1)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			done := make(chan struct{})
+
+			go func() {
+				defer close(done)
+				suite.runner.renew(ctx, cancel, "1")
+			}()
+
+			<-renewed
+
+			if !tc.cancelled {
+				suite.NoError(ctx.Err())
+				cancel()
+			}
+
+			<-done
+			suite.ErrorIs(ctx.Err(), context.Canceled)
+		})
+	}
+}
+
+func TestJobRunnerTestSuite(t *testing.T) {
+	suite.Run(t, new(JobRunnerTestSuite))
+}
@@ -0,0 +1,131 @@
+package news
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/mmcdole/gofeed"
+
+	"go-news-feed/pkg/model"
+)
+
+// paramPatterns caches the compiled strip params patterns of link policies
+var paramPatterns sync.Map
+
+// articleID returns a deterministic id for a feed item based on the source id strategy
+// - guid (default): the item GUID when present, otherwise the hash of the canonical link
+// - link: always the hash of the canonical link, for feeds with unreliable GUIDs
+// items without GUID nor link fall back to the hash of their content
+func articleID(item *gofeed.Item, strategy, canonicalLink, contentHash string) string {
+	guid := strings.TrimSpace(item.GUID)
+	if guid != "" && strategy != model.IDStrategyLink {
+		return guid
+	}
+
+	if canonicalLink != "" {
+		return hash(canonicalLink)
+	}
+
+	if guid != "" {
+		return guid
+	}
+
+	return contentHash
+}
+
+// canonicalLink normalises a link based on the policy provided
+// so the same story reached by two urls is always written the same way
+// scheme is lowercased and default ports are always dropped
+func canonicalLink(link string, policy model.LinkPolicy) string {
+	link = strings.TrimSpace(link)
+
+	u, err := url.Parse(link)
+	if err != nil || u.Host == "" {
+		return link
+	}
+
+	u.Scheme = strings.ToLower(u.Scheme)
+
+	if policy.ForceHTTPS && u.Scheme == "http" {
+		u.Scheme = "https"
+	}
+
+	if policy.LowercaseHost {
+		u.Host = strings.ToLower(u.Host)
+	}
+
+	if port := u.Port(); (u.Scheme == "http" && port == "80") || (u.Scheme == "https" && port == "443") {
+		u.Host = u.Hostname()
+	}
+
+	if u.Path == "" {
+		u.Path = "/"
+	}
+
+	if policy.DropFragment {
+		u.Fragment = ""
+		u.RawFragment = ""
+	}
+
+	if len(policy.StripParams) > 0 && u.RawQuery != "" {
+		query := u.Query()
+
+		for param := range query {
+			if matchesAny(param, policy.StripParams) {
+				query.Del(param)
+			}
+		}
+
+		u.RawQuery = query.Encode()
+	}
+
+	return u.String()
+}
+
+// validateLinkPolicy checks every strip params pattern is a valid regular expression
+func validateLinkPolicy(policy *model.LinkPolicy) error {
+	if policy == nil {
+		return nil
+	}
+
+	for _, pattern := range policy.StripParams {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("%w: invalid strip params pattern %q: %v", ErrInvalidRequest, pattern, err)
+		}
+	}
+
+	return nil
+}
+
+// matchesAny returns true if the value matches any of the patterns
+// invalid patterns never match
+func matchesAny(value string, patterns []string) bool {
+	for _, pattern := range patterns {
+		re, ok := paramPatterns.Load(pattern)
+		if !ok {
+			compiled, err := regexp.Compile(pattern)
+			if err != nil {
+				continue
+			}
+
+			re, _ = paramPatterns.LoadOrStore(pattern, compiled)
+		}
+
+		if re.(*regexp.Regexp).MatchString(value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func hash(value string) string {
+	sum := sha256.Sum256([]byte(value))
+
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,122 @@
+package news
+
+import (
+	"testing"
+
+	"github.com/mmcdole/gofeed"
+	"github.com/stretchr/testify/assert"
+
+	"go-news-feed/pkg/model"
+)
+
+func TestArticleID(t *testing.T) {
+	testCases := []struct {
+		name     string
+		given    *gofeed.Item
+		strategy string
+		expected string
+	}{
+		{
+			name:     "GUID",
+			given:    &gofeed.Item{GUID: "https://www.bbc.co.uk/news/uk-1", Link: "https://www.bbc.co.uk/news/uk-1?at_medium=RSS"},
+			expected: "https://www.bbc.co.uk/news/uk-1",
+		},
+		{
+			name:     "MissingGUID",
+			given:    &gofeed.Item{Link: "https://www.bbc.co.uk/news/uk-1"},
+			expected: hash("https://www.bbc.co.uk/news/uk-1"),
+		},
+		{
+			name:     "MissingGUIDWithCanonicalisedLink",
+			given:    &gofeed.Item{Link: " HTTPS://WWW.BBC.CO.UK:443/news/uk-1#comments "},
+			expected: hash("https://www.bbc.co.uk/news/uk-1"),
+		},
+		{
+			name:     "LinkStrategy",
+			given:    &gofeed.Item{GUID: "42", Link: "https://news.sky.com/story/1"},
+			strategy: model.IDStrategyLink,
+			expected: hash("https://news.sky.com/story/1"),
+		},
+		{
+			name:     "LinkStrategyWithoutLink",
+			given:    &gofeed.Item{GUID: "42"},
+			strategy: model.IDStrategyLink,
+			expected: "42",
+		},
+		{
+			name:     "MissingGUIDAndLink",
+			given:    &gofeed.Item{Title: "title"},
+			expected: "content hash",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			link := canonicalLink(tc.given.Link, model.DefaultLinkPolicy)
+
+			assert.Equal(t, tc.expected, articleID(tc.given, tc.strategy, link, "content hash"))
+		})
+	}
+}
+
+func TestCanonicalLink(t *testing.T) {
+	testCases := []struct {
+		name     string
+		given    string
+		policy   model.LinkPolicy
+		expected string
+	}{
+		{
+			name:     "BBCTrackingParams",
+			given:    "http://www.bbc.co.uk/news/uk-1?at_medium=RSS&at_campaign=KARANGA",
+			policy:   model.ProviderLinkPolicies["bbc"],
+			expected: "https://www.bbc.co.uk/news/uk-1",
+		},
+		{
+			name:     "SkyTrackingParams",
+			given:    "https://news.sky.com/story/1?dcmp=rss&utm_source=feed&page=2",
+			policy:   model.ProviderLinkPolicies["sky"],
+			expected: "https://news.sky.com/story/1?page=2",
+		},
+		{
+			name:     "DefaultPolicy",
+			given:    " HTTP://News.Example.COM:80/story?utm_medium=rss&id=1#top ",
+			policy:   model.DefaultLinkPolicy,
+			expected: "http://news.example.com/story?id=1",
+		},
+		{
+			name:     "EmptyPolicyKeepsLink",
+			given:    "https://News.Example.com/story?utm_medium=rss#top",
+			expected: "https://News.Example.com/story?utm_medium=rss#top",
+		},
+		{
+			name:     "EmptyPath",
+			given:    "https://example.com",
+			expected: "https://example.com/",
+		},
+		{
+			name:     "InvalidPatternIgnored",
+			given:    "https://example.com/story?utm_medium=rss",
+			policy:   model.LinkPolicy{StripParams: []string{"("}},
+			expected: "https://example.com/story?utm_medium=rss",
+		},
+		{
+			name:     "NotAnURL",
+			given:    "story-1",
+			policy:   model.DefaultLinkPolicy,
+			expected: "story-1",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, canonicalLink(tc.given, tc.policy))
+		})
+	}
+}
+
+func TestValidateLinkPolicy(t *testing.T) {
+	assert.NoError(t, validateLinkPolicy(nil))
+	assert.NoError(t, validateLinkPolicy(&model.LinkPolicy{StripParams: []string{"^utm_"}}))
+	assert.ErrorIs(t, validateLinkPolicy(&model.LinkPolicy{StripParams: []string{"("}}), ErrInvalidRequest)
+}
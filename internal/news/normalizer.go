@@ -0,0 +1,170 @@
+package news
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/mmcdole/gofeed"
+	"golang.org/x/net/html/charset"
+
+	"go-news-feed/pkg/model"
+)
+
+// maxInferredTitle is the max number of characters of a title inferred from the description
+const maxInferredTitle = 120
+
+// Inferred fields of an article
+const (
+	inferredPublishedDateTime = "publishedDateTime"
+	inferredTitle             = "title"
+	inferredLink              = "link"
+)
+
+// normalizeArticle cleans up an article mapped from a feed item
+// - text fields are trimmed and made valid utf-8
+// - missing published date is inferred from the updated date, the feed dates or the fetch time
+// - missing title is inferred from the description and missing link from a permalink guid
+// - dates are in UTC
+// the inferred fields are listed in the article
+func normalizeArticle(article *model.Article, feed *gofeed.Feed, fetchedAt time.Time) {
+	article.GUID = cleanText(article.GUID)
+	article.Title = normalizeTitle(article.Title)
+	article.Descriptiopn = cleanText(article.Descriptiopn)
+	article.Link = cleanText(article.Link)
+	article.FeedContent = cleanText(article.FeedContent)
+
+	for i := range article.Authors {
+		article.Authors[i].Name = cleanText(article.Authors[i].Name)
+	}
+
+	for i := range article.Tags {
+		article.Tags[i] = cleanText(article.Tags[i])
+	}
+
+	if article.PublishedDateTime == nil {
+		article.PublishedDateTime = inferPublished(article.UpdatedDateTime, feed, fetchedAt)
+		article.InferredFields = append(article.InferredFields, inferredPublishedDateTime)
+	}
+
+	article.PublishedDateTime = toUTC(article.PublishedDateTime)
+	article.UpdatedDateTime = toUTC(article.UpdatedDateTime)
+
+	if article.Title == "" {
+		if article.Title = truncate(plainText(article.Descriptiopn), maxInferredTitle); article.Title != "" {
+			article.InferredFields = append(article.InferredFields, inferredTitle)
+		}
+	}
+
+	if article.Link == "" && isPermalink(article.GUID) {
+		article.Link = article.GUID
+		article.InferredFields = append(article.InferredFields, inferredLink)
+	}
+}
+
+// inferPublished returns the first date available
+// from the updated date, the feed published and updated dates and the fetch time
+func inferPublished(updated *time.Time, feed *gofeed.Feed, fetchedAt time.Time) *time.Time {
+	candidates := []*time.Time{updated}
+	if feed != nil {
+		candidates = append(candidates, feed.PublishedParsed, feed.UpdatedParsed)
+	}
+
+	for _, candidate := range candidates {
+		if candidate != nil && !candidate.IsZero() {
+			published := *candidate
+			return &published
+		}
+	}
+
+	return &fetchedAt
+}
+
+func toUTC(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+
+	utc := t.UTC()
+
+	return &utc
+}
+
+// normalizeTitle cleans up the title and collapses its whitespace
+func normalizeTitle(title string) string {
+	return strings.Join(strings.Fields(cleanText(title)), " ")
+}
+
+// cleanText trims the value and replaces invalid utf-8 sequences
+func cleanText(value string) string {
+	return strings.TrimSpace(strings.ToValidUTF8(value, "�"))
+}
+
+// truncate the value to limit characters, on a word boundary when possible
+func truncate(value string, limit int) string {
+	runes := []rune(value)
+	if len(runes) <= limit {
+		return value
+	}
+
+	truncated := string(runes[:limit])
+	if i := strings.LastIndex(truncated, " "); i > 0 {
+		truncated = truncated[:i]
+	}
+
+	return truncated + "…"
+}
+
+func isPermalink(guid string) bool {
+	return strings.HasPrefix(guid, "http://") || strings.HasPrefix(guid, "https://")
+}
+
+// toUTF8 transcodes a feed body which isn't valid utf-8 based on the charset of its content type
+// feeds declaring another encoding in their xml prolog are left to the parser
+func toUTF8(body []byte, contentType string) ([]byte, error) {
+	if utf8.Valid(body) {
+		return body, nil
+	}
+
+	if encoding := declaredEncoding(body); encoding != "" && encoding != "utf-8" && encoding != "utf8" {
+		return body, nil
+	}
+
+	r, err := charset.NewReader(bytes.NewReader(body), contentType)
+	if err != nil {
+		return nil, err
+	}
+
+	return io.ReadAll(r)
+}
+
+// declaredEncoding returns the lowercased encoding of the xml prolog, if any
+func declaredEncoding(body []byte) string {
+	body = bytes.TrimSpace(bytes.TrimPrefix(body, []byte("\xef\xbb\xbf")))
+	if !bytes.HasPrefix(body, []byte("<?xml")) {
+		return ""
+	}
+
+	end := bytes.Index(body, []byte("?>"))
+	if end < 0 {
+		return ""
+	}
+
+	prolog := string(body[:end])
+
+	i := strings.Index(prolog, "encoding=")
+	if i < 0 || len(prolog) <= i+len("encoding=") {
+		return ""
+	}
+
+	value := prolog[i+len("encoding="):]
+	quote := value[0]
+
+	if j := strings.IndexByte(value[1:], quote); (quote == '"' || quote == '\'') && j >= 0 {
+		return strings.ToLower(value[1 : j+1])
+	}
+
+	return ""
+}
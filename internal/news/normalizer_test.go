@@ -0,0 +1,134 @@
+package news
+
+import (
+	"bytes"
+	"os"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-news-feed/pkg/model"
+)
+
+func TestNormalizeFeed(t *testing.T) {
+	fetchedAt := time.Date(2024, 4, 6, 15, 0, 0, 0, time.UTC)
+
+	type expectedArticle struct {
+		title     string
+		link      string
+		published time.Time
+		inferred  []string
+	}
+
+	testCases := []struct {
+		name        string
+		given       string
+		contentType string
+		expected    []expectedArticle
+	}{
+		{
+			name:  "MissingFields",
+			given: "testdata/missing_fields.xml",
+			expected: []expectedArticle{
+				{
+					title:     "Council approves new cycle lanes",
+					link:      "https://local.example.com/cycle-lanes",
+					published: time.Date(2024, 4, 6, 8, 30, 0, 0, time.UTC),
+				},
+				{
+					// from the feed date
+					title:     "Library opening hours extended",
+					link:      "https://local.example.com/library",
+					published: time.Date(2024, 4, 6, 7, 0, 0, 0, time.UTC),
+					inferred:  []string{"publishedDateTime"},
+				},
+				{
+					title:     "Roadworks on the high street will last until the end of the summer, the council has confirmed.",
+					link:      "https://local.example.com/roadworks",
+					published: time.Date(2024, 4, 6, 7, 0, 0, 0, time.UTC),
+					inferred:  []string{"publishedDateTime", "title", "link"},
+				},
+			},
+		},
+		{
+			name:  "MissingDates",
+			given: "testdata/missing_dates.atom",
+			expected: []expectedArticle{
+				{
+					// gofeed already falls back to the updated date of atom entries
+					title:     "Bridge reopens after repairs",
+					link:      "https://atom.example.com/bridge",
+					published: time.Date(2024, 4, 6, 11, 0, 0, 0, time.UTC),
+				},
+				{
+					// from the fetch time
+					title:     "Market returns to the square",
+					link:      "https://atom.example.com/market",
+					published: fetchedAt,
+					inferred:  []string{"publishedDateTime"},
+				},
+			},
+		},
+		{
+			name:        "CharsetFromContentType",
+			given:       "testdata/windows1252.xml",
+			contentType: "application/rss+xml; charset=windows-1252",
+			expected: []expectedArticle{
+				{
+					title:     "Café owners “delighted” by award – report",
+					link:      "https://cafe.example.com/award",
+					published: time.Date(2024, 4, 6, 9, 0, 0, 0, time.UTC),
+				},
+			},
+		},
+		{
+			name:        "CharsetFromProlog",
+			given:       "testdata/latin1.xml",
+			contentType: "application/rss+xml",
+			expected: []expectedArticle{
+				{
+					title:     `Café owners "delighted" by award - report`,
+					link:      "https://cafe.example.com/award",
+					published: time.Date(2024, 4, 6, 9, 0, 0, 0, time.UTC),
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			body, err := os.ReadFile(tc.given)
+			require.NoError(t, err)
+
+			body, err = toUTF8(body, tc.contentType)
+			require.NoError(t, err)
+
+			feed, err := gofeed.NewParser().Parse(bytes.NewReader(body))
+			require.NoError(t, err)
+
+			articles, err := (&service{}).parseFeed(feed, model.Source{}, fetchedAt)
+			require.NoError(t, err)
+			require.Len(t, articles, len(tc.expected))
+
+			for i, expected := range tc.expected {
+				assert.Equal(t, expected.title, articles[i].Title)
+				assert.Equal(t, expected.link, articles[i].Link)
+				assert.Equal(t, expected.published, *articles[i].PublishedDateTime)
+				assert.Equal(t, time.UTC, articles[i].PublishedDateTime.Location())
+				assert.Equal(t, expected.inferred, articles[i].InferredFields)
+			}
+		})
+	}
+}
+
+func TestSortArticlesWithoutDate(t *testing.T) {
+	published := time.Date(2024, 4, 6, 9, 0, 0, 0, time.UTC)
+	articles := model.Articles{{ID: "1", PublishedDateTime: &published}, {ID: "2"}}
+
+	assert.NotPanics(t, func() { sort.Sort(articles) })
+	assert.Equal(t, "2", articles[0].ID)
+}
@@ -0,0 +1,178 @@
+package news
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/mmcdole/gofeed"
+
+	"go-news-feed/pkg/model"
+)
+
+// validateArticles returns the valid articles and the invalid ones to quarantine
+// articles are mapped from the feed items of the same index
+func (s *service) validateArticles(source model.Source, items []*gofeed.Item, articles []model.Article, fetchedAt time.Time) ([]model.Article, []model.QuarantinedItem) {
+	var (
+		valid   = make([]model.Article, 0, len(articles))
+		invalid = make([]model.QuarantinedItem, 0)
+	)
+
+	for i, article := range articles {
+		reason := s.validateArticle(items[i], article)
+		if reason == "" {
+			valid = append(valid, article)
+			continue
+		}
+
+		quarantined := model.QuarantinedItem{
+			ID:            article.ID,
+			Source:        source.Reference(),
+			Article:       article,
+			Reason:        reason,
+			QuarantinedAt: fetchedAt,
+		}
+
+		// the item is kept as parsed so it can be mapped again once reprocessed
+		if raw, err := json.Marshal(items[i]); err == nil {
+			quarantined.Raw = raw
+		}
+
+		invalid = append(invalid, quarantined)
+	}
+
+	return valid, invalid
+}
+
+// discardedReason is why an article isn't quarantined again once it has been discarded
+const discardedReason = "discarded from quarantine"
+
+// skipDiscarded returns the items to quarantine without the ones discarded before
+// which are returned as dropped instead
+func (s *service) skipDiscarded(ctx context.Context, items []model.QuarantinedItem) ([]model.QuarantinedItem, []model.ItemOutcome, error) {
+	if len(items) == 0 {
+		return items, nil, nil
+	}
+
+	ids := make([]string, len(items))
+	for i, item := range items {
+		ids[i] = item.ID
+	}
+
+	discarded, err := s.quarantineRepository.FindDiscarded(ctx, ids)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(discarded) == 0 {
+		return items, nil, nil
+	}
+
+	var (
+		kept    = make([]model.QuarantinedItem, 0, len(items))
+		dropped = make([]model.ItemOutcome, 0, len(discarded))
+	)
+
+	for _, item := range items {
+		if slices.Contains(discarded, item.ID) {
+			dropped = append(dropped, model.NewItemOutcome(item.Article, model.OutcomeFilter, discardedReason))
+			continue
+		}
+
+		kept = append(kept, item)
+	}
+
+	return kept, dropped, nil
+}
+
+// validateArticle returns why the article mapped from the item can't be stored, if so
+// the source is validated when it's registered so it's left out
+func (s *service) validateArticle(item *gofeed.Item, article model.Article) string {
+	reasons := make([]string, 0)
+
+	if err := s.validator.StructExcept(article, "Source"); err != nil {
+		var errs validator.ValidationErrors
+		if !errors.As(err, &errs) {
+			return err.Error()
+		}
+
+		for _, fe := range errs {
+			reasons = append(reasons, fmt.Sprintf("%s: %s", fe.Field(), fe.Tag()))
+		}
+	}
+
+	// a date the parser can't read isn't inferred silently
+	if item != nil && strings.TrimSpace(item.Published) != "" && item.PublishedParsed == nil {
+		reasons = append(reasons, fmt.Sprintf("unparseable published date %q", item.Published))
+	}
+
+	if item != nil && strings.TrimSpace(item.Updated) != "" && item.UpdatedParsed == nil {
+		reasons = append(reasons, fmt.Sprintf("unparseable updated date %q", item.Updated))
+	}
+
+	return strings.Join(reasons, "; ")
+}
+
+// reprocessItem maps (if it has a raw item) and validates a quarantined item again
+// and runs it through the pipeline and the rules, with the current settings of the source
+// it returns the article and why it's still rejected, if so
+func (s *service) reprocessItem(ctx context.Context, source model.Source, item model.QuarantinedItem) (model.Article, string, error) {
+	article := item.Article
+	article.Source = source.Reference()
+
+	var feedItem *gofeed.Item
+
+	if len(item.Raw) > 0 {
+		if err := json.Unmarshal(item.Raw, &feedItem); err != nil {
+			return model.Article{}, "", err
+		}
+
+		articles, err := s.parseFeed(&gofeed.Feed{Items: []*gofeed.Item{feedItem}}, source, item.QuarantinedAt)
+		if err != nil {
+			return model.Article{}, "", err
+		}
+
+		article = articles[0]
+	}
+
+	if reason := s.validateArticle(feedItem, article); reason != "" {
+		return article, reason, nil
+	}
+
+	p, err := s.stages.Build(source.Pipeline)
+	if err != nil {
+		return model.Article{}, "", err
+	}
+
+	processed, err := p.Run(ctx, source, []model.Article{article})
+	if err != nil {
+		return model.Article{}, "", err
+	}
+
+	if len(processed) == 0 {
+		return article, droppedReason, nil
+	}
+
+	article = processed[0]
+
+	stored, err := s.ruleRepository.FindAll(ctx)
+	if err != nil {
+		return model.Article{}, "", err
+	}
+
+	rules, err := compileRules(stored)
+	if err != nil {
+		return model.Article{}, "", err
+	}
+
+	if rule, rejected := rejectedBy(rules, source, article, time.Now().UTC()); rejected {
+		return article, rule.reason(), nil
+	}
+
+	return article, "", nil
+}
@@ -0,0 +1,182 @@
+package news
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"go-news-feed/pkg/model"
+)
+
+// QuarantineRepository - interface
+//
+//go:generate mockgen -source=quarantine_repository.go -destination=quarantine_repository_mock.go --package=news
+type QuarantineRepository interface {
+	CreateMany(ctx context.Context, items []model.QuarantinedItem) error
+	Find(ctx context.Context, qr model.QuarantineRequest) (model.QuarantineResponse, error)
+	FindByID(ctx context.Context, id string) (model.QuarantinedItem, error)
+	FindDiscarded(ctx context.Context, ids []string) ([]string, error)
+	Update(ctx context.Context, item model.QuarantinedItem) error
+	Discard(ctx context.Context, id string, discardedAt time.Time) error
+	Delete(ctx context.Context, id string) error
+}
+
+type quarantineRepository struct {
+	collection *mongo.Collection
+}
+
+// newQuarantineRepository - constructor
+func newQuarantineRepository(ctx context.Context, db *mongo.Database, config MongoConfig) (QuarantineRepository, error) {
+	collection := db.Collection(config.QuarantineCollection)
+
+	_, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "source._id", Value: 1}, {Key: "quarantinedAt", Value: -1}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &quarantineRepository{collection: collection}, nil
+}
+
+// CreateMany inserts the items
+// items already quarantined (or discarded) by a previous load are left as they are
+func (r quarantineRepository) CreateMany(ctx context.Context, items []model.QuarantinedItem) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	documents := make([]any, len(items))
+	for i := range items {
+		documents[i] = items[i]
+	}
+
+	_, err := r.collection.InsertMany(ctx, documents, options.InsertMany().SetOrdered(false))
+	if _, err := duplicateKeyErrors(err); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Find returns the items quarantined (from the source, if any), latest first
+func (r quarantineRepository) Find(ctx context.Context, qr model.QuarantineRequest) (model.QuarantineResponse, error) {
+	filter := bson.M{"discardedAt": bson.M{"$exists": false}}
+	if qr.SourceID != "" {
+		filter["source._id"] = qr.SourceID
+	}
+
+	limit := qr.Limit
+	if limit == 0 || limit > maxLimit {
+		limit = maxLimit
+	}
+
+	total, err := r.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return model.QuarantineResponse{}, err
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "quarantinedAt", Value: -1}, {Key: "_id", Value: 1}}).
+		SetSkip(int64(qr.Page * limit)).
+		SetLimit(int64(limit))
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return model.QuarantineResponse{}, err
+	}
+
+	items := make([]model.QuarantinedItem, 0)
+	if err := cursor.All(ctx, &items); err != nil {
+		return model.QuarantineResponse{}, err
+	}
+
+	return model.QuarantineResponse{Criteria: qr, Items: items, Total: int(total)}, nil
+}
+
+// FindByID returns the item unless it has been discarded
+func (r quarantineRepository) FindByID(ctx context.Context, id string) (model.QuarantinedItem, error) {
+	var item model.QuarantinedItem
+
+	if err := r.collection.FindOne(ctx, bson.M{"_id": id, "discardedAt": bson.M{"$exists": false}}).Decode(&item); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return model.QuarantinedItem{}, ErrNotFound
+		}
+
+		return model.QuarantinedItem{}, err
+	}
+
+	return item, nil
+}
+
+// FindDiscarded returns the ids of the discarded items among the ids provided
+func (r quarantineRepository) FindDiscarded(ctx context.Context, ids []string) ([]string, error) {
+	if len(ids) == 0 {
+		return []string{}, nil
+	}
+
+	filter := bson.M{"_id": bson.M{"$in": ids}, "discardedAt": bson.M{"$exists": true}}
+
+	cursor, err := r.collection.Find(ctx, filter, options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		return nil, err
+	}
+
+	var items []model.QuarantinedItem
+	if err := cursor.All(ctx, &items); err != nil {
+		return nil, err
+	}
+
+	discarded := make([]string, len(items))
+	for i, item := range items {
+		discarded[i] = item.ID
+	}
+
+	return discarded, nil
+}
+
+func (r quarantineRepository) Update(ctx context.Context, item model.QuarantinedItem) error {
+	result, err := r.collection.ReplaceOne(ctx, bson.M{"_id": item.ID, "discardedAt": bson.M{"$exists": false}}, &item)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// Discard marks the item as discarded, it's no longer listed
+func (r quarantineRepository) Discard(ctx context.Context, id string, discardedAt time.Time) error {
+	filter := bson.M{"_id": id, "discardedAt": bson.M{"$exists": false}}
+
+	result, err := r.collection.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"discardedAt": discardedAt}})
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+func (r quarantineRepository) Delete(ctx context.Context, id string) error {
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+
+	if result.DeletedCount == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
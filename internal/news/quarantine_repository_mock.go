@@ -0,0 +1,138 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: quarantine_repository.go
+
+// Package news is a generated GoMock package.
+package news
+
+import (
+	context "context"
+	model "go-news-feed/pkg/model"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockQuarantineRepository is a mock of QuarantineRepository interface.
+type MockQuarantineRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockQuarantineRepositoryMockRecorder
+}
+
+// MockQuarantineRepositoryMockRecorder is the mock recorder for MockQuarantineRepository.
+type MockQuarantineRepositoryMockRecorder struct {
+	mock *MockQuarantineRepository
+}
+
+// NewMockQuarantineRepository creates a new mock instance.
+func NewMockQuarantineRepository(ctrl *gomock.Controller) *MockQuarantineRepository {
+	mock := &MockQuarantineRepository{ctrl: ctrl}
+	mock.recorder = &MockQuarantineRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockQuarantineRepository) EXPECT() *MockQuarantineRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateMany mocks base method.
+func (m *MockQuarantineRepository) CreateMany(ctx context.Context, items []model.QuarantinedItem) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateMany", ctx, items)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateMany indicates an expected call of CreateMany.
+func (mr *MockQuarantineRepositoryMockRecorder) CreateMany(ctx, items interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateMany", reflect.TypeOf((*MockQuarantineRepository)(nil).CreateMany), ctx, items)
+}
+
+// Delete mocks base method.
+func (m *MockQuarantineRepository) Delete(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockQuarantineRepositoryMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockQuarantineRepository)(nil).Delete), ctx, id)
+}
+
+// Discard mocks base method.
+func (m *MockQuarantineRepository) Discard(ctx context.Context, id string, discardedAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Discard", ctx, id, discardedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Discard indicates an expected call of Discard.
+func (mr *MockQuarantineRepositoryMockRecorder) Discard(ctx, id, discardedAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Discard", reflect.TypeOf((*MockQuarantineRepository)(nil).Discard), ctx, id, discardedAt)
+}
+
+// Find mocks base method.
+func (m *MockQuarantineRepository) Find(ctx context.Context, qr model.QuarantineRequest) (model.QuarantineResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Find", ctx, qr)
+	ret0, _ := ret[0].(model.QuarantineResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Find indicates an expected call of Find.
+func (mr *MockQuarantineRepositoryMockRecorder) Find(ctx, qr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockQuarantineRepository)(nil).Find), ctx, qr)
+}
+
+// FindByID mocks base method.
+func (m *MockQuarantineRepository) FindByID(ctx context.Context, id string) (model.QuarantinedItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByID", ctx, id)
+	ret0, _ := ret[0].(model.QuarantinedItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByID indicates an expected call of FindByID.
+func (mr *MockQuarantineRepositoryMockRecorder) FindByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockQuarantineRepository)(nil).FindByID), ctx, id)
+}
+
+// FindDiscarded mocks base method.
+func (m *MockQuarantineRepository) FindDiscarded(ctx context.Context, ids []string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindDiscarded", ctx, ids)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindDiscarded indicates an expected call of FindDiscarded.
+func (mr *MockQuarantineRepositoryMockRecorder) FindDiscarded(ctx, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindDiscarded", reflect.TypeOf((*MockQuarantineRepository)(nil).FindDiscarded), ctx, ids)
+}
+
+// Update mocks base method.
+func (m *MockQuarantineRepository) Update(ctx context.Context, item model.QuarantinedItem) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, item)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockQuarantineRepositoryMockRecorder) Update(ctx, item interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockQuarantineRepository)(nil).Update), ctx, item)
+}
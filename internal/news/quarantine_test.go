@@ -0,0 +1,64 @@
+package news
+
+import (
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/mmcdole/gofeed"
+	"github.com/stretchr/testify/assert"
+
+	"go-news-feed/pkg/model"
+)
+
+func TestValidateArticle(t *testing.T) {
+	s := &service{validator: validator.New()}
+
+	valid := model.Article{ID: "1", Title: "Council approves new cycle lanes", Link: "https://local.example.com/cycle-lanes"}
+
+	testCases := []struct {
+		name     string
+		item     *gofeed.Item
+		article  func(article model.Article) model.Article
+		expected string
+	}{
+		{
+			name:     "Valid",
+			item:     &gofeed.Item{},
+			expected: "",
+		},
+		{
+			name: "SourceIsLeftOut",
+			item: &gofeed.Item{},
+			article: func(article model.Article) model.Article {
+				article.Source = model.Source{ID: "unregistered"}
+				return article
+			},
+			expected: "",
+		},
+		{
+			name: "MissingTitleAndLink",
+			item: &gofeed.Item{},
+			article: func(article model.Article) model.Article {
+				article.Title, article.Link = "", ""
+				return article
+			},
+			expected: "Title: required; Link: required",
+		},
+		{
+			name:     "UnparseableDates",
+			item:     &gofeed.Item{Published: "yesterday", Updated: "today"},
+			expected: `unparseable published date "yesterday"; unparseable updated date "today"`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			article := valid
+			if tc.article != nil {
+				article = tc.article(article)
+			}
+
+			assert.Equal(t, tc.expected, s.validateArticle(tc.item, article))
+		})
+	}
+}
@@ -2,7 +2,9 @@ package news
 
 import (
 	"context"
+	"errors"
 	"strings"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -15,6 +17,8 @@ import (
 const (
 	orderDesc = "desc"
 	maxLimit  = 1000
+	// maxSimilar is the max number of candidates returned when looking up near duplicates
+	maxSimilar = 500
 )
 
 // Repository - interface
@@ -23,15 +27,34 @@ const (
 type Repository interface {
 	FindByID(ctx context.Context, id string) (model.Article, error)
 	Find(ctx context.Context, fr model.FindRequest) (model.FindResponse, error)
-	Create(ctx context.Context, article model.Article) error
+	FindByIDs(ctx context.Context, ids []string) ([]model.Article, error)
+	BulkUpsert(ctx context.Context, articles []model.Article) (UpsertResult, error)
+	BulkUpdate(ctx context.Context, updates []ArticleUpdate) (int, error)
+	FindByStoryID(ctx context.Context, storyID string) ([]model.Article, error)
+	FindSimilar(ctx context.Context, bands []string, from, to time.Time) ([]model.Article, error)
+	FindRecent(ctx context.Context, sourceID string, limit int) ([]model.Article, error)
+}
+
+// ArticleUpdate replaces a stored article as long as it's still on the previous revision
+type ArticleUpdate struct {
+	Article          model.Article
+	PreviousRevision int
+}
+
+// UpsertResult - outcome of a bulk upsert
+type UpsertResult struct {
+	// Created is the number of articles inserted
+	Created int
+	// Existing is the number of articles already stored
+	Existing int
 }
 
 type repository struct {
 	collection *mongo.Collection
 }
 
-// newRepository - constructor
-func newRepository(ctx context.Context, config MongoConfig) (Repository, error) {
+// newDatabase connects to mongo and returns the configured database
+func newDatabase(ctx context.Context, config MongoConfig) (*mongo.Database, error) {
 	client, err := mongo.Connect(ctx, options.Client().ApplyURI(config.URI))
 
 	if err != nil {
@@ -44,7 +67,38 @@ func newRepository(ctx context.Context, config MongoConfig) (Repository, error)
 		return nil, err
 	}
 
-	collection := client.Database(config.Database).Collection(config.Collection)
+	return client.Database(config.Database), nil
+}
+
+// newRepository - constructor
+// it also makes sure the same canonical link can't be stored twice
+// articles stored before links were canonicalised are left out of the index
+func newRepository(ctx context.Context, db *mongo.Database, config MongoConfig) (Repository, error) {
+	collection := db.Collection(config.Collection)
+
+	_, err := collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "canonicalLink", Value: 1}},
+			Options: options.Index().
+				SetUnique(true).
+				SetPartialFilterExpression(bson.M{"canonicalLink": bson.M{"$type": "string"}}),
+		},
+		{
+			Keys: bson.D{{Key: "storyId", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "authors.name", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "tags", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "fingerprintBands", Value: 1}, {Key: "publishedDateTime", Value: 1}},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
 
 	return &repository{collection: collection}, nil
 }
@@ -53,12 +107,113 @@ func (r repository) FindByID(ctx context.Context, id string) (model.Article, err
 	var article model.Article
 
 	if err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&article); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return model.Article{}, ErrNotFound
+		}
+
 		return model.Article{}, err
 	}
 
 	return article, nil
 }
 
+func (r repository) FindByIDs(ctx context.Context, ids []string) ([]model.Article, error) {
+	articles := make([]model.Article, 0)
+
+	if len(ids) == 0 {
+		return articles, nil
+	}
+
+	cursor, err := r.collection.Find(ctx, bson.M{"_id": bson.M{"$in": ids}})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cursor.All(ctx, &articles); err != nil {
+		return nil, err
+	}
+
+	return articles, nil
+}
+
+// FindByStoryID returns the articles of a story ordered by published time (asc)
+// articles stored before stories were introduced are their own story
+func (r repository) FindByStoryID(ctx context.Context, storyID string) ([]model.Article, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "publishedDateTime", Value: 1}})
+
+	filter := bson.M{"$or": bson.A{
+		bson.M{"storyId": storyID},
+		bson.M{"_id": storyID, "storyId": bson.M{"$exists": false}},
+	}}
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	articles := make([]model.Article, 0)
+	if err := cursor.All(ctx, &articles); err != nil {
+		return nil, err
+	}
+
+	return articles, nil
+}
+
+// FindSimilar returns the articles published between from and to
+// sharing at least one fingerprint band with the bands provided
+// up to the latest maxSimilar ones
+func (r repository) FindSimilar(ctx context.Context, bands []string, from, to time.Time) ([]model.Article, error) {
+	articles := make([]model.Article, 0)
+
+	if len(bands) == 0 {
+		return articles, nil
+	}
+
+	filter := bson.M{
+		"fingerprintBands":  bson.M{"$in": bands},
+		"publishedDateTime": bson.M{"$gte": from, "$lte": to},
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "publishedDateTime", Value: -1}, {Key: "_id", Value: 1}}).
+		SetLimit(maxSimilar)
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cursor.All(ctx, &articles); err != nil {
+		return nil, err
+	}
+
+	return articles, nil
+}
+
+// FindRecent returns the latest articles published (of the source, if any)
+func (r repository) FindRecent(ctx context.Context, sourceID string, limit int) ([]model.Article, error) {
+	filter := bson.M{}
+	if sourceID != "" {
+		filter["source._id"] = sourceID
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "publishedDateTime", Value: -1}}).
+		SetLimit(int64(limit))
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	articles := make([]model.Article, 0)
+	if err := cursor.All(ctx, &articles); err != nil {
+		return nil, err
+	}
+
+	return articles, nil
+}
+
 func (r repository) Find(ctx context.Context, fr model.FindRequest) (model.FindResponse, error) {
 	pipeline := mongo.Pipeline{}
 
@@ -70,10 +225,34 @@ func (r repository) Find(ctx context.Context, fr model.FindRequest) (model.FindR
 		pipeline = append(pipeline, r.buildFilterStage("source.provider", fr.Provider))
 	}
 
+	if fr.Author != "" {
+		pipeline = append(pipeline, r.buildFilterStage("authors.name", fr.Author))
+	}
+
+	if fr.Tag != "" {
+		pipeline = append(pipeline, r.buildFilterStage("tags", fr.Tag))
+	}
+
+	if fr.HasImage {
+		pipeline = append(pipeline, r.buildFilterStage("image.url", bson.D{{Key: "$exists", Value: true}}))
+	}
+
 	if fr.Sort != "" {
 		pipeline = append(pipeline, r.buildOrderStage(fr.Sort, fr.Order))
 	}
 
+	if fr.Collapse == model.CollapseStory {
+		pipeline = append(pipeline, r.buildCollapseStages(fr.Sort)...)
+
+		// grouping doesn't keep the order, the id breaks ties so the pages don't overlap
+		sort, order := fr.Sort, fr.Order
+		if sort == "" {
+			sort, order = "publishedDateTime", orderDesc
+		}
+
+		pipeline = append(pipeline, r.buildStableOrderStage(sort, order))
+	}
+
 	pipeline = append(pipeline,
 		r.buildFacetStage(fr.Page, fr.Limit),
 		r.buildProjectStage(),
@@ -89,13 +268,95 @@ func (r repository) Find(ctx context.Context, fr model.FindRequest) (model.FindR
 	return response, nil
 }
 
-func (r repository) Create(ctx context.Context, article model.Article) error {
-	_, err := r.collection.InsertOne(ctx, &article)
+// BulkUpsert inserts the articles that don't exist yet in a single unordered bulk write
+// articles are keyed on their id so duplicates are resolved atomically by the database
+func (r repository) BulkUpsert(ctx context.Context, articles []model.Article) (UpsertResult, error) {
+	if len(articles) == 0 {
+		return UpsertResult{}, nil
+	}
+
+	models := make([]mongo.WriteModel, len(articles))
+	for i, article := range articles {
+		models[i] = mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": article.ID}).
+			SetUpdate(bson.M{"$setOnInsert": article}).
+			SetUpsert(true)
+	}
+
+	result, err := r.collection.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false))
+
+	// concurrent upserts of the same id or articles with the canonical link
+	// of a stored article fail with a duplicate key error
+	// it means the article has been stored already
+	duplicates, err := duplicateKeyErrors(err)
 	if err != nil {
-		return err
+		return UpsertResult{}, err
+	}
+
+	if result == nil {
+		result = &mongo.BulkWriteResult{}
+	}
+
+	return UpsertResult{
+		Created:  int(result.UpsertedCount),
+		Existing: int(result.MatchedCount) + duplicates,
+	}, nil
+}
+
+// BulkUpdate replaces the articles in a single unordered bulk write
+// an article is only replaced if it's still on the previous revision
+// so concurrent loads can't overwrite each other, it returns the number of articles updated
+func (r repository) BulkUpdate(ctx context.Context, updates []ArticleUpdate) (int, error) {
+	if len(updates) == 0 {
+		return 0, nil
+	}
+
+	models := make([]mongo.WriteModel, len(updates))
+	for i, update := range updates {
+		// articles stored before revisions were tracked don't have the field
+		revision := any(update.PreviousRevision)
+		if update.PreviousRevision == 0 {
+			revision = bson.M{"$in": bson.A{nil, 0}}
+		}
+
+		models[i] = mongo.NewReplaceOneModel().
+			SetFilter(bson.M{"_id": update.Article.ID, "revision": revision}).
+			SetReplacement(update.Article)
+	}
+
+	result, err := r.collection.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false))
+
+	// an update can't take the canonical link of another stored article
+	if _, err := duplicateKeyErrors(err); err != nil {
+		return 0, err
+	}
+
+	if result == nil {
+		return 0, nil
+	}
+
+	return int(result.ModifiedCount), nil
+}
+
+// duplicateKeyErrors returns the number of duplicate key write errors of a bulk write
+// or the error itself if it contains any other kind of error
+func duplicateKeyErrors(err error) (int, error) {
+	if err == nil {
+		return 0, nil
 	}
 
-	return nil
+	var bwe mongo.BulkWriteException
+	if !errors.As(err, &bwe) || bwe.WriteConcernError != nil {
+		return 0, err
+	}
+
+	for _, we := range bwe.WriteErrors {
+		if !mongo.IsDuplicateKeyError(we) {
+			return 0, err
+		}
+	}
+
+	return len(bwe.WriteErrors), nil
 }
 
 func (r repository) aggregate(ctx context.Context, pipeline mongo.Pipeline, opts ...*options.AggregateOptions) (model.FindResponse, error) {
@@ -113,7 +374,7 @@ func (r repository) aggregate(ctx context.Context, pipeline mongo.Pipeline, opts
 }
 
 // buildFilterStage used to filter by field provided
-func (r repository) buildFilterStage(field, value string) bson.D {
+func (r repository) buildFilterStage(field string, value any) bson.D {
 	return bson.D{
 		{
 			Key: "$match", Value: bson.D{
@@ -141,6 +402,62 @@ func (r repository) buildOrderStage(sort, order string) bson.D {
 	}
 }
 
+// buildStableOrderStage used to sort on a field and then on the id
+// so the order of the articles is the same on every request
+func (r repository) buildStableOrderStage(sort, order string) bson.D {
+	orderN := 1
+
+	if strings.ToLower(order) == orderDesc {
+		orderN = -1
+	}
+
+	return bson.D{
+		{
+			Key: "$sort", Value: bson.D{
+				{Key: sort, Value: orderN},
+				{Key: "_id", Value: 1},
+			},
+		},
+	}
+}
+
+// buildCollapseStages used to keep a single article per story
+// the first one in the requested order, or the first published one
+// articles stored before stories were introduced are their own story
+func (r repository) buildCollapseStages(sort string) []bson.D {
+	stages := make([]bson.D, 0, 3)
+
+	if sort == "" {
+		stages = append(stages, r.buildOrderStage("publishedDateTime", ""))
+	}
+
+	return append(stages,
+		bson.D{
+			{
+				Key: "$group", Value: bson.D{
+					{
+						Key: "_id", Value: bson.D{
+							{Key: "$ifNull", Value: bson.A{"$storyId", "$_id"}},
+						},
+					},
+					{
+						Key: "article", Value: bson.D{
+							{Key: "$first", Value: "$$ROOT"},
+						},
+					},
+				},
+			},
+		},
+		bson.D{
+			{
+				Key: "$replaceRoot", Value: bson.D{
+					{Key: "newRoot", Value: "$article"},
+				},
+			},
+		},
+	)
+}
+
 // buildFacetStage used to process multiple aggregation pipelines within a single stage
 // specifying the sub-pipeline output.
 // - Count Stage
@@ -8,6 +8,7 @@ import (
 	context "context"
 	model "go-news-feed/pkg/model"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 )
@@ -35,18 +36,34 @@ func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
 	return m.recorder
 }
 
-// Create mocks base method.
-func (m *MockRepository) Create(ctx context.Context, article model.Article) error {
+// BulkUpdate mocks base method.
+func (m *MockRepository) BulkUpdate(ctx context.Context, updates []ArticleUpdate) (int, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", ctx, article)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "BulkUpdate", ctx, updates)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// Create indicates an expected call of Create.
-func (mr *MockRepositoryMockRecorder) Create(ctx, article interface{}) *gomock.Call {
+// BulkUpdate indicates an expected call of BulkUpdate.
+func (mr *MockRepositoryMockRecorder) BulkUpdate(ctx, updates interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockRepository)(nil).Create), ctx, article)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BulkUpdate", reflect.TypeOf((*MockRepository)(nil).BulkUpdate), ctx, updates)
+}
+
+// BulkUpsert mocks base method.
+func (m *MockRepository) BulkUpsert(ctx context.Context, articles []model.Article) (UpsertResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BulkUpsert", ctx, articles)
+	ret0, _ := ret[0].(UpsertResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BulkUpsert indicates an expected call of BulkUpsert.
+func (mr *MockRepositoryMockRecorder) BulkUpsert(ctx, articles interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BulkUpsert", reflect.TypeOf((*MockRepository)(nil).BulkUpsert), ctx, articles)
 }
 
 // Find mocks base method.
@@ -78,3 +95,63 @@ func (mr *MockRepositoryMockRecorder) FindByID(ctx, id interface{}) *gomock.Call
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockRepository)(nil).FindByID), ctx, id)
 }
+
+// FindByIDs mocks base method.
+func (m *MockRepository) FindByIDs(ctx context.Context, ids []string) ([]model.Article, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByIDs", ctx, ids)
+	ret0, _ := ret[0].([]model.Article)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByIDs indicates an expected call of FindByIDs.
+func (mr *MockRepositoryMockRecorder) FindByIDs(ctx, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByIDs", reflect.TypeOf((*MockRepository)(nil).FindByIDs), ctx, ids)
+}
+
+// FindByStoryID mocks base method.
+func (m *MockRepository) FindByStoryID(ctx context.Context, storyID string) ([]model.Article, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByStoryID", ctx, storyID)
+	ret0, _ := ret[0].([]model.Article)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByStoryID indicates an expected call of FindByStoryID.
+func (mr *MockRepositoryMockRecorder) FindByStoryID(ctx, storyID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByStoryID", reflect.TypeOf((*MockRepository)(nil).FindByStoryID), ctx, storyID)
+}
+
+// FindRecent mocks base method.
+func (m *MockRepository) FindRecent(ctx context.Context, sourceID string, limit int) ([]model.Article, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindRecent", ctx, sourceID, limit)
+	ret0, _ := ret[0].([]model.Article)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindRecent indicates an expected call of FindRecent.
+func (mr *MockRepositoryMockRecorder) FindRecent(ctx, sourceID, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindRecent", reflect.TypeOf((*MockRepository)(nil).FindRecent), ctx, sourceID, limit)
+}
+
+// FindSimilar mocks base method.
+func (m *MockRepository) FindSimilar(ctx context.Context, bands []string, from, to time.Time) ([]model.Article, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindSimilar", ctx, bands, from, to)
+	ret0, _ := ret[0].([]model.Article)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindSimilar indicates an expected call of FindSimilar.
+func (mr *MockRepositoryMockRecorder) FindSimilar(ctx, bands, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindSimilar", reflect.TypeOf((*MockRepository)(nil).FindSimilar), ctx, bands, from, to)
+}
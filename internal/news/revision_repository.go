@@ -0,0 +1,77 @@
+package news
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"go-news-feed/pkg/model"
+)
+
+// RevisionRepository - interface
+//
+//go:generate mockgen -source=revision_repository.go -destination=revision_repository_mock.go --package=news
+type RevisionRepository interface {
+	FindByArticleID(ctx context.Context, articleID string) ([]model.Revision, error)
+	CreateMany(ctx context.Context, revisions []model.Revision) error
+}
+
+type revisionRepository struct {
+	collection *mongo.Collection
+}
+
+// newRevisionRepository - constructor
+func newRevisionRepository(ctx context.Context, db *mongo.Database, config MongoConfig) (RevisionRepository, error) {
+	collection := db.Collection(config.RevisionCollection)
+
+	_, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "articleId", Value: 1}, {Key: "revision", Value: 1}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &revisionRepository{collection: collection}, nil
+}
+
+// FindByArticleID returns the revisions of an article ordered by revision (asc)
+func (r revisionRepository) FindByArticleID(ctx context.Context, articleID string) ([]model.Revision, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "revision", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, bson.M{"articleId": articleID}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	revisions := make([]model.Revision, 0)
+	if err := cursor.All(ctx, &revisions); err != nil {
+		return nil, err
+	}
+
+	return revisions, nil
+}
+
+// CreateMany inserts the revisions
+// the id of a revision is derived from the article id and revision number
+// so the same revision recorded by concurrent loads is only stored once
+func (r revisionRepository) CreateMany(ctx context.Context, revisions []model.Revision) error {
+	if len(revisions) == 0 {
+		return nil
+	}
+
+	documents := make([]any, len(revisions))
+	for i, revision := range revisions {
+		revision.ID = fmt.Sprintf("%s#%d", revision.ArticleID, revision.Revision)
+		documents[i] = revision
+	}
+
+	_, err := r.collection.InsertMany(ctx, documents, options.InsertMany().SetOrdered(false))
+	if _, err := duplicateKeyErrors(err); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,65 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: revision_repository.go
+
+// Package news is a generated GoMock package.
+package news
+
+import (
+	context "context"
+	model "go-news-feed/pkg/model"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockRevisionRepository is a mock of RevisionRepository interface.
+type MockRevisionRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRevisionRepositoryMockRecorder
+}
+
+// MockRevisionRepositoryMockRecorder is the mock recorder for MockRevisionRepository.
+type MockRevisionRepositoryMockRecorder struct {
+	mock *MockRevisionRepository
+}
+
+// NewMockRevisionRepository creates a new mock instance.
+func NewMockRevisionRepository(ctrl *gomock.Controller) *MockRevisionRepository {
+	mock := &MockRevisionRepository{ctrl: ctrl}
+	mock.recorder = &MockRevisionRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRevisionRepository) EXPECT() *MockRevisionRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateMany mocks base method.
+func (m *MockRevisionRepository) CreateMany(ctx context.Context, revisions []model.Revision) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateMany", ctx, revisions)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateMany indicates an expected call of CreateMany.
+func (mr *MockRevisionRepositoryMockRecorder) CreateMany(ctx, revisions interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateMany", reflect.TypeOf((*MockRevisionRepository)(nil).CreateMany), ctx, revisions)
+}
+
+// FindByArticleID mocks base method.
+func (m *MockRevisionRepository) FindByArticleID(ctx context.Context, articleID string) ([]model.Revision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByArticleID", ctx, articleID)
+	ret0, _ := ret[0].([]model.Revision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByArticleID indicates an expected call of FindByArticleID.
+func (mr *MockRevisionRepositoryMockRecorder) FindByArticleID(ctx, articleID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByArticleID", reflect.TypeOf((*MockRevisionRepository)(nil).FindByArticleID), ctx, articleID)
+}
@@ -0,0 +1,265 @@
+package news
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// robotsAgent is the user agent token matched against the robots.txt groups
+const robotsAgent = "go-news-feed"
+
+// maxRobotsBytes is the max size of a robots.txt, the rest is ignored
+const maxRobotsBytes = 512 << 10
+
+// maxCrawlDelay caps the Crawl-delay of a robots.txt
+const maxCrawlDelay = time.Minute
+
+// robotsRetry is how long a robots.txt that can't be fetched is considered unreachable
+// so a host that is down isn't asked for it by every extraction
+const robotsRetry = time.Minute
+
+// robots are the rules of a robots.txt that apply to our user agent
+type robots struct {
+	rules      []robotsRule
+	crawlDelay time.Duration
+	expiresAt  time.Time
+	// err is why the robots.txt can't be fetched, every page of the host is disallowed meanwhile
+	err error
+}
+
+type robotsRule struct {
+	allow   bool
+	length  int
+	pattern *regexp.Regexp
+}
+
+// allowed returns true if the path (with its query) can be fetched
+// the longest matching rule wins and allow wins a tie
+func (r robots) allowed(path string) bool {
+	var (
+		allow  = true
+		length = -1
+	)
+
+	for _, rule := range r.rules {
+		if !rule.pattern.MatchString(path) {
+			continue
+		}
+
+		if rule.length > length || (rule.length == length && rule.allow) {
+			allow, length = rule.allow, rule.length
+		}
+	}
+
+	return allow
+}
+
+// parseRobots returns the rules of the groups naming our user agent
+// or of the * groups if none does
+func parseRobots(r io.Reader) robots {
+	type group struct {
+		agents     []string
+		rules      []robotsRule
+		crawlDelay time.Duration
+	}
+
+	var (
+		groups  []*group
+		current *group
+		// rules end the user-agent lines of a group
+		inRules bool
+	)
+
+	scanner := bufio.NewScanner(io.LimitReader(r, maxRobotsBytes))
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+
+		key, value = strings.ToLower(strings.TrimSpace(key)), strings.TrimSpace(value)
+
+		switch key {
+		case "user-agent":
+			if current == nil || inRules {
+				current = &group{}
+				groups = append(groups, current)
+				inRules = false
+			}
+
+			current.agents = append(current.agents, strings.ToLower(value))
+		case "allow", "disallow":
+			if current == nil {
+				continue
+			}
+
+			inRules = true
+
+			// an empty disallow allows everything
+			if value == "" {
+				continue
+			}
+
+			current.rules = append(current.rules, robotsRule{
+				allow:   key == "allow",
+				length:  len(value),
+				pattern: robotsPattern(value),
+			})
+		case "crawl-delay":
+			if current == nil {
+				continue
+			}
+
+			inRules = true
+
+			if seconds, err := strconv.ParseFloat(value, 64); err == nil && seconds > 0 {
+				current.crawlDelay = min(time.Duration(seconds*float64(time.Second)), maxCrawlDelay)
+			}
+		}
+	}
+
+	var matched, wildcard robots
+
+	for _, g := range groups {
+		for _, agent := range g.agents {
+			target := &wildcard
+			if agent != "*" {
+				if agent != robotsAgent {
+					continue
+				}
+
+				target = &matched
+			}
+
+			target.rules = append(target.rules, g.rules...)
+			target.crawlDelay = max(target.crawlDelay, g.crawlDelay)
+
+			break
+		}
+	}
+
+	if matched.rules != nil || matched.crawlDelay > 0 {
+		return matched
+	}
+
+	return wildcard
+}
+
+// robotsPattern converts a robots.txt path, where * matches any characters
+// and a trailing $ the end of the path, to a regexp matching its prefix
+func robotsPattern(path string) *regexp.Regexp {
+	end := strings.HasSuffix(path, "$")
+	path = strings.TrimSuffix(path, "$")
+
+	expr := "^" + strings.ReplaceAll(regexp.QuoteMeta(path), `\*`, ".*")
+	if end {
+		expr += "$"
+	}
+
+	return regexp.MustCompile(expr)
+}
+
+// robotsFor returns the robots.txt rules of the host of the page, cached for the configured TTL
+// a missing robots.txt allows everything while an unreachable one disallows everything
+// and is requested again after a while
+// concurrent extractions of a host share a single fetch of its robots.txt
+func (e *extractor) robotsFor(ctx context.Context, page *url.URL) (robots, error) {
+	e.mu.Lock()
+	cached, ok := e.robots[page.Host]
+	e.mu.Unlock()
+
+	if ok && time.Now().Before(cached.expiresAt) {
+		return cached, cached.err
+	}
+
+	lookup := e.lookups.DoChan(page.Host, func() (any, error) {
+		// the fetch is shared so it isn't cancelled with the extraction that started it
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), e.config.Timeout)
+		defer cancel()
+
+		rules, err := e.fetchRobots(ctx, page)
+
+		switch {
+		case errors.Is(err, ErrHostBusy):
+			// the host is only busy with our other requests
+		case err != nil:
+			rules = robots{err: err, expiresAt: time.Now().Add(robotsRetry)}
+		default:
+			rules.expiresAt = time.Now().Add(e.config.RobotsTTL)
+		}
+
+		if !rules.expiresAt.IsZero() {
+			e.mu.Lock()
+			e.robots[page.Host] = rules
+			e.mu.Unlock()
+		}
+
+		if rules.crawlDelay > 0 {
+			e.limiter.setCrawlDelay(page.Host, rules.crawlDelay)
+		}
+
+		return rules, err
+	})
+
+	select {
+	case <-ctx.Done():
+		return robots{}, ctx.Err()
+	case result := <-lookup:
+		if result.Err != nil {
+			return robots{}, result.Err
+		}
+
+		return result.Val.(robots), nil
+	}
+}
+
+func (e *extractor) fetchRobots(ctx context.Context, page *url.URL) (robots, error) {
+	robotsURL := &url.URL{Scheme: page.Scheme, Host: page.Host, Path: "/robots.txt"}
+
+	for range maxRedirects + 1 {
+		rules, next, err := e.getRobots(ctx, robotsURL)
+		if err != nil || next == nil {
+			return rules, err
+		}
+
+		robotsURL = next
+	}
+
+	return robots{}, fmt.Errorf("failed to fetch robots.txt: stopped after %d redirects", maxRedirects)
+}
+
+// getRobots fetches a robots.txt, it returns the url redirected to instead, if any
+func (e *extractor) getRobots(ctx context.Context, robotsURL *url.URL) (robots, *url.URL, error) {
+	if robotsURL.Scheme != "http" && robotsURL.Scheme != "https" {
+		return robots{}, nil, fmt.Errorf("failed to fetch robots.txt: unsupported url %s", robotsURL)
+	}
+
+	resp, err := e.get(ctx, robotsURL, "")
+	if err != nil {
+		return robots{}, nil, fmt.Errorf("failed to fetch robots.txt: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if next, err := redirectLocation(resp); err != nil || next != nil {
+		return robots{}, next, err
+	}
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return parseRobots(resp.Body), nil, nil
+	case resp.StatusCode >= 400 && resp.StatusCode < 500:
+		return robots{}, nil, nil
+	default:
+		return robots{}, nil, fmt.Errorf("failed to fetch robots.txt: %w", statusError{StatusCode: resp.StatusCode, Status: resp.Status})
+	}
+}
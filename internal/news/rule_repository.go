@@ -0,0 +1,108 @@
+package news
+
+import (
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"go-news-feed/pkg/model"
+)
+
+// RuleRepository - interface
+//
+//go:generate mockgen -source=rule_repository.go -destination=rule_repository_mock.go --package=news
+type RuleRepository interface {
+	FindByID(ctx context.Context, id string) (model.Rule, error)
+	FindAll(ctx context.Context) ([]model.Rule, error)
+	Create(ctx context.Context, rule model.Rule) error
+	Update(ctx context.Context, rule model.Rule) error
+	Delete(ctx context.Context, id string) error
+}
+
+type ruleRepository struct {
+	collection *mongo.Collection
+}
+
+// newRuleRepository - constructor
+func newRuleRepository(ctx context.Context, db *mongo.Database, config MongoConfig) (RuleRepository, error) {
+	collection := db.Collection(config.RuleCollection)
+
+	_, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "sourceId", Value: 1}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &ruleRepository{collection: collection}, nil
+}
+
+func (r ruleRepository) FindByID(ctx context.Context, id string) (model.Rule, error) {
+	var rule model.Rule
+
+	if err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&rule); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return model.Rule{}, ErrNotFound
+		}
+
+		return model.Rule{}, err
+	}
+
+	return rule, nil
+}
+
+func (r ruleRepository) FindAll(ctx context.Context) ([]model.Rule, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+
+	rules := make([]model.Rule, 0)
+	if err := cursor.All(ctx, &rules); err != nil {
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+func (r ruleRepository) Create(ctx context.Context, rule model.Rule) error {
+	_, err := r.collection.InsertOne(ctx, &rule)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return ErrAlreadyExists
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+func (r ruleRepository) Update(ctx context.Context, rule model.Rule) error {
+	result, err := r.collection.ReplaceOne(ctx, bson.M{"_id": rule.ID}, &rule)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+func (r ruleRepository) Delete(ctx context.Context, id string) error {
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+
+	if result.DeletedCount == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,257 @@
+package news
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"go-news-feed/pkg/model"
+)
+
+// compiledRule is a rule ready to be matched against articles
+type compiledRule struct {
+	model.Rule
+	title       *regexp.Regexp
+	description *regexp.Regexp
+	link        *regexp.Regexp
+	keywords    *regexp.Regexp
+	olderThan   time.Duration
+}
+
+// compileRule checks the conditions of the rule the validator can't check
+// i.e. the regular expressions and the age, and that at least one condition is set
+func compileRule(rule model.Rule) (compiledRule, error) {
+	compiled := compiledRule{Rule: rule}
+
+	if compiled.Action == "" {
+		compiled.Action = model.RuleActionDrop
+	}
+
+	patterns := []struct {
+		field   string
+		pattern string
+		target  **regexp.Regexp
+	}{
+		{"title", rule.Title, &compiled.title},
+		{"description", rule.Description, &compiled.description},
+		{"link", rule.Link, &compiled.link},
+	}
+
+	conditions := 0
+
+	for _, p := range patterns {
+		if p.pattern == "" {
+			continue
+		}
+
+		re, err := regexp.Compile(p.pattern)
+		if err != nil {
+			return compiledRule{}, fmt.Errorf("%w: invalid %s pattern %q: %v", ErrInvalidRequest, p.field, p.pattern, err)
+		}
+
+		*p.target = re
+		conditions++
+	}
+
+	if len(rule.Keywords) > 0 {
+		keywords := make([]string, 0, len(rule.Keywords))
+		for _, keyword := range rule.Keywords {
+			if keyword = strings.TrimSpace(keyword); keyword != "" {
+				keywords = append(keywords, keywordPattern(keyword))
+			}
+		}
+
+		if len(keywords) == 0 {
+			return compiledRule{}, fmt.Errorf("%w: empty keywords", ErrInvalidRequest)
+		}
+
+		compiled.keywords = regexp.MustCompile(`(?i)(` + strings.Join(keywords, "|") + `)`)
+		conditions++
+	}
+
+	if len(rule.Categories) > 0 {
+		conditions++
+	}
+
+	if rule.OlderThan != "" {
+		age, err := time.ParseDuration(rule.OlderThan)
+		if err != nil || age <= 0 {
+			return compiledRule{}, fmt.Errorf("%w: invalid age %q", ErrInvalidRequest, rule.OlderThan)
+		}
+
+		compiled.olderThan = age
+		conditions++
+	}
+
+	if conditions == 0 {
+		return compiledRule{}, fmt.Errorf("%w: rule %q has no condition", ErrInvalidRequest, rule.Name)
+	}
+
+	return compiled, nil
+}
+
+// keywordPattern matches the keyword as a whole word
+// a word boundary is only required on an edge that is a word character
+// e.g. "Live:" matches "Live: Storm Kathleen latest" but not "Olive: prices"
+func keywordPattern(keyword string) string {
+	pattern := regexp.QuoteMeta(keyword)
+
+	if isWordChar(keyword[0]) {
+		pattern = `\b` + pattern
+	}
+
+	if isWordChar(keyword[len(keyword)-1]) {
+		pattern += `\b`
+	}
+
+	return pattern
+}
+
+// isWordChar returns true for the ASCII characters \b considers part of a word
+func isWordChar(c byte) bool {
+	return c == '_' || ('0' <= c && c <= '9') || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z')
+}
+
+// compileRules compiles the rules stored
+func compileRules(rules []model.Rule) ([]compiledRule, error) {
+	compiled := make([]compiledRule, len(rules))
+
+	for i, rule := range rules {
+		var err error
+		if compiled[i], err = compileRule(rule); err != nil {
+			return nil, fmt.Errorf("rule %s: %w", rule.ID, err)
+		}
+	}
+
+	return compiled, nil
+}
+
+// appliesTo returns true for global rules and the rules of the source
+func (r compiledRule) appliesTo(source model.Source) bool {
+	return r.SourceID == "" || r.SourceID == source.ID
+}
+
+// matches returns true if the article matches every condition of the rule
+func (r compiledRule) matches(article model.Article, now time.Time) bool {
+	if r.title != nil && !r.title.MatchString(article.Title) {
+		return false
+	}
+
+	description := article.DescriptionText
+	if description == "" {
+		description = plainText(article.Descriptiopn)
+	}
+
+	if r.description != nil && !r.description.MatchString(description) {
+		return false
+	}
+
+	if r.link != nil && !r.link.MatchString(article.Link) {
+		return false
+	}
+
+	if r.keywords != nil && !r.keywords.MatchString(article.Title) && !r.keywords.MatchString(description) {
+		return false
+	}
+
+	if len(r.Categories) > 0 && !matchesCategory(r.Categories, article) {
+		return false
+	}
+
+	if r.olderThan > 0 && (article.PublishedDateTime == nil || now.Sub(*article.PublishedDateTime) <= r.olderThan) {
+		return false
+	}
+
+	return true
+}
+
+// reason an article is rejected by the rule
+func (r compiledRule) reason() string {
+	return fmt.Sprintf("%s rule %q", r.Type, r.Name)
+}
+
+// matchesCategory returns true if the source category or a tag of the article is one of the categories
+func matchesCategory(categories []string, article model.Article) bool {
+	for _, category := range categories {
+		if strings.EqualFold(category, article.Source.Category) {
+			return true
+		}
+
+		for _, tag := range article.Tags {
+			if strings.EqualFold(category, tag) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// rejectedBy returns the rule rejecting the article, if any
+// block rules are checked first, then the article must match one of the allow rules of the source
+// an article matching none of them is rejected by the first one
+func rejectedBy(rules []compiledRule, source model.Source, article model.Article, now time.Time) (compiledRule, bool) {
+	var allow []compiledRule
+
+	for _, rule := range rules {
+		if !rule.appliesTo(source) {
+			continue
+		}
+
+		if rule.Type == model.RuleAllow {
+			allow = append(allow, rule)
+			continue
+		}
+
+		if rule.matches(article, now) {
+			return rule, true
+		}
+	}
+
+	for _, rule := range allow {
+		if rule.matches(article, now) {
+			return compiledRule{}, false
+		}
+	}
+
+	if len(allow) > 0 {
+		return allow[0], true
+	}
+
+	return compiledRule{}, false
+}
+
+// applyRules returns the articles accepted by the rules of the source
+// along with the articles to quarantine and the articles dropped
+func applyRules(rules []compiledRule, source model.Source, articles []model.Article, now time.Time) ([]model.Article, []model.QuarantinedItem, []model.ItemOutcome) {
+	var (
+		kept        = make([]model.Article, 0, len(articles))
+		quarantined = make([]model.QuarantinedItem, 0)
+		dropped     = make([]model.ItemOutcome, 0)
+	)
+
+	for _, article := range articles {
+		rule, rejected := rejectedBy(rules, source, article, now)
+		if !rejected {
+			kept = append(kept, article)
+			continue
+		}
+
+		if rule.Action != model.RuleActionQuarantine {
+			dropped = append(dropped, model.NewItemOutcome(article, model.OutcomeFilter, rule.reason()))
+			continue
+		}
+
+		quarantined = append(quarantined, model.QuarantinedItem{
+			ID:            article.ID,
+			Source:        source.Reference(),
+			Article:       article,
+			Reason:        rule.reason(),
+			RuleID:        rule.ID,
+			QuarantinedAt: now,
+		})
+	}
+
+	return kept, quarantined, dropped
+}
@@ -0,0 +1,181 @@
+package news
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-news-feed/pkg/model"
+)
+
+func TestRuleMatches(t *testing.T) {
+	now := time.Date(2024, 5, 14, 10, 0, 0, 0, time.UTC)
+	published := now.Add(-96 * time.Hour)
+
+	article := model.Article{
+		Title:             "Sponsored: The best broadband deals this month",
+		Descriptiopn:      "<p>Compare <b>fibre</b> deals from every provider.</p>",
+		Link:              "https://news.sky.com/promoted/broadband-deals",
+		Source:            model.Source{Category: model.CategoryTechnology},
+		Tags:              []string{"Money"},
+		PublishedDateTime: &published,
+	}
+
+	testCases := []struct {
+		name     string
+		given    model.Rule
+		expected bool
+	}{
+		{
+			name:     "Title",
+			given:    model.Rule{Title: "^Sponsored:"},
+			expected: true,
+		},
+		{
+			name:     "DescriptionPlainText",
+			given:    model.Rule{Description: "Compare fibre deals"},
+			expected: true,
+		},
+		{
+			name:     "Link",
+			given:    model.Rule{Link: "/promoted/"},
+			expected: true,
+		},
+		{
+			name:     "KeywordIgnoringCase",
+			given:    model.Rule{Keywords: []string{"FIBRE", "5G"}},
+			expected: true,
+		},
+		{
+			name:     "KeywordWholeWordOnly",
+			given:    model.Rule{Keywords: []string{"deal"}},
+			expected: false,
+		},
+		{
+			name:     "CategoryOfSource",
+			given:    model.Rule{Categories: []string{"Technology"}},
+			expected: true,
+		},
+		{
+			name:     "CategoryOfTags",
+			given:    model.Rule{Categories: []string{"money"}},
+			expected: true,
+		},
+		{
+			name:     "OlderThan",
+			given:    model.Rule{OlderThan: "72h"},
+			expected: true,
+		},
+		{
+			name:     "NotOlderThan",
+			given:    model.Rule{OlderThan: "168h"},
+			expected: false,
+		},
+		{
+			name:     "EveryCondition",
+			given:    model.Rule{Title: "^Sponsored:", Categories: []string{"politics"}},
+			expected: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rule, err := compileRule(tc.given)
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.expected, rule.matches(article, now))
+		})
+	}
+}
+
+func TestKeywordBoundaries(t *testing.T) {
+	testCases := []struct {
+		name     string
+		keyword  string
+		given    string
+		expected bool
+	}{
+		{
+			name:     "TrailingPunctuationFollowedBySpace",
+			keyword:  "Live:",
+			given:    "Live: Storm Kathleen latest",
+			expected: true,
+		},
+		{
+			name:     "TrailingPunctuationAtEnd",
+			keyword:  "Live:",
+			given:    "Storm Kathleen updates live:",
+			expected: true,
+		},
+		{
+			name:     "TrailingPunctuationWithinWord",
+			keyword:  "Live:",
+			given:    "Olive: oil prices soar",
+			expected: false,
+		},
+		{
+			name:     "LeadingPunctuation",
+			keyword:  "#ad",
+			given:    "Best broadband deals #ad",
+			expected: true,
+		},
+		{
+			name:     "LeadingPunctuationWithinWord",
+			keyword:  "#ad",
+			given:    "Trending #adventure holidays",
+			expected: false,
+		},
+		{
+			name:     "Symbols",
+			keyword:  "C++",
+			given:    "What's new in C++ 26",
+			expected: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rule, err := compileRule(model.Rule{Keywords: []string{tc.keyword}})
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.expected, rule.matches(model.Article{Title: tc.given}, time.Now()))
+		})
+	}
+}
+
+func TestApplyRules(t *testing.T) {
+	now := time.Date(2024, 5, 14, 10, 0, 0, 0, time.UTC)
+	source := model.Source{ID: "sky", Category: model.CategoryUK}
+
+	articles := []model.Article{
+		{ID: "1", Title: "Live: Storm Kathleen latest"},
+		{ID: "2", Title: "Sponsored: Broadband deals"},
+		{ID: "3", Title: "Storm Kathleen: Amber warning as 70mph winds hit UK"},
+		{ID: "4", Title: "Election turnout lowest since 2001"},
+	}
+
+	rules, err := compileRules([]model.Rule{
+		{ID: "live", Name: "no live blogs", Type: model.RuleBlock, Title: "^Live:"},
+		{ID: "sponsored", Name: "sponsored", Type: model.RuleBlock, Action: model.RuleActionQuarantine, Title: "^Sponsored:"},
+		{ID: "storms", Name: "storms only", SourceID: "sky", Type: model.RuleAllow, Keywords: []string{"storm"}, Action: model.RuleActionQuarantine},
+		{ID: "bbc", Name: "bbc only", SourceID: "bbc", Type: model.RuleAllow, Keywords: []string{"bbc"}},
+	})
+	require.NoError(t, err)
+
+	kept, quarantined, dropped := applyRules(rules, source, articles, now)
+
+	assert.Equal(t, []model.Article{articles[2]}, kept)
+	assert.Len(t, dropped, 1)
+	require.Len(t, quarantined, 2)
+	assert.Equal(t, "2", quarantined[0].ID)
+	assert.Equal(t, "sponsored", quarantined[0].RuleID)
+	assert.Equal(t, "4", quarantined[1].ID)
+	assert.Equal(t, `allow rule "storms only"`, quarantined[1].Reason)
+	assert.Equal(t, now, quarantined[1].QuarantinedAt)
+
+	// rules of other sources are ignored
+	kept, _, _ = applyRules(rules[3:], source, articles, now)
+	assert.Equal(t, articles, kept)
+}
@@ -0,0 +1,288 @@
+package news
+
+import (
+	"html"
+	"net/url"
+	"strings"
+
+	xhtml "golang.org/x/net/html"
+
+	"go-news-feed/pkg/model"
+)
+
+const (
+	formatHTML = "html"
+	formatText = "text"
+)
+
+// allowedTags are the tags kept by the sanitizer with their allowed attributes
+// any other tag is dropped but its text is kept
+var allowedTags = map[string][]string{
+	"a": {"href", "title"}, "abbr": {"title"}, "b": nil, "blockquote": nil, "br": nil, "code": nil,
+	"em": nil, "figcaption": nil, "figure": nil, "h1": nil, "h2": nil, "h3": nil, "h4": nil, "h5": nil,
+	"h6": nil, "hr": nil, "i": nil, "img": {"src", "alt", "title", "width", "height"}, "li": nil,
+	"mark": nil, "ol": nil, "p": nil, "pre": nil, "s": nil, "small": nil, "strong": nil, "sub": nil,
+	"sup": nil, "table": nil, "tbody": nil, "td": nil, "th": nil, "thead": nil, "tr": nil, "u": nil, "ul": nil,
+}
+
+// droppedTags are removed along with everything inside them
+var droppedTags = map[string]bool{
+	"script": true, "style": true, "iframe": true, "object": true, "embed": true, "noscript": true,
+	"template": true, "svg": true, "math": true, "head": true, "title": true, "form": true,
+	"select": true, "textarea": true, "frameset": true, "frame": true, "applet": true,
+}
+
+// voidTags never have an end tag
+var voidTags = map[string]bool{"br": true, "hr": true, "img": true}
+
+// blockTags separate lines of the plain text version
+var blockTags = map[string]bool{
+	"address": true, "article": true, "blockquote": true, "br": true, "div": true, "dd": true, "dt": true,
+	"figcaption": true, "figure": true, "footer": true, "h1": true, "h2": true, "h3": true, "h4": true,
+	"h5": true, "h6": true, "header": true, "hr": true, "li": true, "ol": true, "p": true, "pre": true,
+	"section": true, "table": true, "td": true, "th": true, "tr": true, "ul": true,
+}
+
+// sanitizeHTML returns the markup keeping only the allowed tags and attributes
+// links and images are only kept with http(s) (or relative) urls
+// and links are rendered with rel="nofollow noopener noreferrer"
+func sanitizeHTML(value string) string {
+	var (
+		b       strings.Builder
+		open    = make([]string, 0)
+		dropped = 0
+	)
+
+	z := xhtml.NewTokenizer(strings.NewReader(value))
+
+	for {
+		tt := z.Next()
+		if tt == xhtml.ErrorToken {
+			break
+		}
+
+		token := z.Token()
+
+		switch tt {
+		case xhtml.TextToken:
+			if dropped == 0 {
+				b.WriteString(html.EscapeString(token.Data))
+			}
+		case xhtml.StartTagToken, xhtml.SelfClosingTagToken:
+			if droppedTags[token.Data] {
+				if tt == xhtml.StartTagToken {
+					dropped++
+				}
+
+				continue
+			}
+
+			attrs, ok := allowedTags[token.Data]
+			if !ok || dropped > 0 {
+				continue
+			}
+
+			b.WriteString("<" + token.Data)
+
+			for _, attr := range token.Attr {
+				if !contains(attrs, attr.Key) {
+					continue
+				}
+
+				if (attr.Key == "href" || attr.Key == "src") && !isSafeURL(attr.Val) {
+					continue
+				}
+
+				b.WriteString(" " + attr.Key + `="` + html.EscapeString(attr.Val) + `"`)
+			}
+
+			if token.Data == "a" {
+				b.WriteString(` rel="nofollow noopener noreferrer"`)
+			}
+
+			b.WriteString(">")
+
+			if !voidTags[token.Data] {
+				open = append(open, token.Data)
+			}
+		case xhtml.EndTagToken:
+			if droppedTags[token.Data] {
+				dropped = max(0, dropped-1)
+				continue
+			}
+
+			if dropped > 0 {
+				continue
+			}
+
+			// elements left open within the closed one are closed first
+			for i := len(open) - 1; i >= 0; i-- {
+				if open[i] != token.Data {
+					continue
+				}
+
+				for j := len(open) - 1; j >= i; j-- {
+					b.WriteString("</" + open[j] + ">")
+				}
+
+				open = open[:i]
+
+				break
+			}
+		}
+	}
+
+	for i := len(open) - 1; i >= 0; i-- {
+		b.WriteString("</" + open[i] + ">")
+	}
+
+	return strings.TrimSpace(b.String())
+}
+
+// plainText returns the text of the markup with entities decoded
+// block elements are rendered on their own line and whitespaces are collapsed
+func plainText(value string) string {
+	var (
+		b       strings.Builder
+		dropped = 0
+	)
+
+	z := xhtml.NewTokenizer(strings.NewReader(value))
+
+	for {
+		tt := z.Next()
+		if tt == xhtml.ErrorToken {
+			break
+		}
+
+		token := z.Token()
+
+		switch tt {
+		case xhtml.TextToken:
+			if dropped == 0 {
+				b.WriteString(token.Data)
+			}
+		case xhtml.StartTagToken, xhtml.SelfClosingTagToken:
+			if droppedTags[token.Data] && tt == xhtml.StartTagToken {
+				dropped++
+			}
+
+			if blockTags[token.Data] {
+				b.WriteString("\n")
+			}
+		case xhtml.EndTagToken:
+			if droppedTags[token.Data] {
+				dropped = max(0, dropped-1)
+			}
+
+			if blockTags[token.Data] {
+				b.WriteString("\n")
+			}
+		}
+	}
+
+	lines := make([]string, 0)
+
+	for _, line := range strings.Split(b.String(), "\n") {
+		if line = strings.Join(strings.Fields(line), " "); line != "" {
+			lines = append(lines, line)
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// isSafeURL returns true for http(s) and mailto urls and relative ones
+func isSafeURL(value string) bool {
+	u, err := url.Parse(strings.TrimSpace(value))
+	if err != nil {
+		return false
+	}
+
+	switch strings.ToLower(u.Scheme) {
+	case "", "http", "https", "mailto":
+		return true
+	default:
+		return false
+	}
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// renderArticle returns the article with its description and feed content
+// in the format requested, so the raw markup of the feed is never served
+// articles stored before sanitisation are sanitised on the fly
+func renderArticle(article model.Article, format string) model.Article {
+	if article.Descriptiopn != "" && article.DescriptionHTML == "" && article.DescriptionText == "" {
+		article.DescriptionHTML = sanitizeHTML(article.Descriptiopn)
+		article.DescriptionText = plainText(article.Descriptiopn)
+	}
+
+	if article.FeedContent != "" && article.FeedContentHTML == "" && article.FeedContentText == "" {
+		article.FeedContentHTML = sanitizeHTML(article.FeedContent)
+		article.FeedContentText = plainText(article.FeedContent)
+	}
+
+	if format == formatText {
+		article.Descriptiopn = article.DescriptionText
+		article.FeedContent = article.FeedContentText
+	} else {
+		article.Descriptiopn = article.DescriptionHTML
+		article.FeedContent = article.FeedContentHTML
+	}
+
+	return article
+}
+
+// renderArticles renders every article in the format requested
+func renderArticles(articles []model.Article, format string) []model.Article {
+	rendered := make([]model.Article, len(articles))
+	for i, article := range articles {
+		rendered[i] = renderArticle(article, format)
+	}
+
+	return rendered
+}
+
+// renderQuarantinedItems renders the articles of quarantined items in the format requested
+func renderQuarantinedItems(items []model.QuarantinedItem, format string) []model.QuarantinedItem {
+	rendered := make([]model.QuarantinedItem, len(items))
+	for i, item := range items {
+		item.Article = renderArticle(item.Article, format)
+		rendered[i] = item
+	}
+
+	return rendered
+}
+
+// renderChanges renders the description changes of revisions in the format requested
+func renderChanges(diffs []model.RevisionDiff, format string) []model.RevisionDiff {
+	render := sanitizeHTML
+	if format == formatText {
+		render = plainText
+	}
+
+	for i := range diffs {
+		changes := make([]model.FieldChange, len(diffs[i].Changes))
+
+		for j, change := range diffs[i].Changes {
+			if change.Field == "description" {
+				change.From, change.To = render(change.From), render(change.To)
+			}
+
+			changes[j] = change
+		}
+
+		diffs[i].Changes = changes
+	}
+
+	return diffs
+}
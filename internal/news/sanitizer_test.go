@@ -0,0 +1,84 @@
+package news
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go-news-feed/pkg/model"
+)
+
+func TestSanitize(t *testing.T) {
+	testCases := []struct {
+		name         string
+		given        string
+		expectedHTML string
+		expectedText string
+	}{
+		{
+			name:         "PlainText",
+			given:        "Ministers say the plan will cut bills",
+			expectedHTML: "Ministers say the plan will cut bills",
+			expectedText: "Ministers say the plan will cut bills",
+		},
+		{
+			name:         "Entities",
+			given:        "Fish &amp; chips &lt;3 at &quot;Joe&#39;s&quot;",
+			expectedHTML: "Fish &amp; chips &lt;3 at &#34;Joe&#39;s&#34;",
+			expectedText: `Fish & chips <3 at "Joe's"`,
+		},
+		{
+			name:         "AllowedTags",
+			given:        `<p>Read <a href="https://www.bbc.co.uk/news" onclick="steal()" class="link">more</a> <b>now</b></p>`,
+			expectedHTML: `<p>Read <a href="https://www.bbc.co.uk/news" rel="nofollow noopener noreferrer">more</a> <b>now</b></p>`,
+			expectedText: "Read more now",
+		},
+		{
+			name:         "ScriptsAndFrames",
+			given:        `<div>Breaking<script>alert("x")</script><iframe src="https://evil.example.com">frame</iframe> news</div><style>p{}</style>`,
+			expectedHTML: "Breaking news",
+			expectedText: "Breaking news",
+		},
+		{
+			name:         "UnsafeURLs",
+			given:        `<a href="javascript:alert(1)">click</a><img src="data:image/png;base64,AAAA" alt="pixel"><img src="/thumb.jpg">`,
+			expectedHTML: `<a rel="nofollow noopener noreferrer">click</a><img alt="pixel"><img src="/thumb.jpg">`,
+			expectedText: "click",
+		},
+		{
+			name:         "UnclosedAndStrayTags",
+			given:        "<p>First <em>line</p></b><p>Second line",
+			expectedHTML: "<p>First <em>line</em></p><p>Second line</p>",
+			expectedText: "First line\nSecond line",
+		},
+		{
+			name:         "BlockElements",
+			given:        "<ul><li>One</li><li>Two</li></ul>Three<br>Four",
+			expectedHTML: "<ul><li>One</li><li>Two</li></ul>Three<br>Four",
+			expectedText: "One\nTwo\nThree\nFour",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedHTML, sanitizeHTML(tc.given))
+			assert.Equal(t, tc.expectedText, plainText(tc.given))
+		})
+	}
+}
+
+func TestRenderArticle(t *testing.T) {
+	article := model.Article{
+		Descriptiopn:    `<p>Breaking <script>x()</script>news</p>`,
+		DescriptionHTML: "<p>Breaking news</p>",
+		DescriptionText: "Breaking news",
+		FeedContent:     `<b>Full</b> story`,
+	}
+
+	assert.Equal(t, "<p>Breaking news</p>", renderArticle(article, formatHTML).Descriptiopn)
+	assert.Equal(t, "Breaking news", renderArticle(article, formatText).Descriptiopn)
+
+	// articles stored before sanitisation
+	assert.Equal(t, "<b>Full</b> story", renderArticle(article, formatHTML).FeedContent)
+	assert.Equal(t, "Full story", renderArticle(article, formatText).FeedContent)
+}
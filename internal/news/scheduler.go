@@ -0,0 +1,182 @@
+package news
+
+import (
+	"context"
+	"log"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"go-news-feed/pkg/model"
+)
+
+// scheduler polls every registered source on its own interval
+// through the same Service.Load path used by the /load endpoint
+type scheduler struct {
+	service          Service
+	sourceRepository SourceRepository
+	config           SchedulerConfig
+	now              func() time.Time
+
+	mu       sync.Mutex
+	nextRuns map[string]time.Time
+	running  map[string]bool
+	wg       sync.WaitGroup
+}
+
+// newScheduler - constructor
+func newScheduler(service Service, sourceRepository SourceRepository, config SchedulerConfig) *scheduler {
+	return &scheduler{
+		service:          service,
+		sourceRepository: sourceRepository,
+		config:           config,
+		now:              time.Now,
+		nextRuns:         make(map[string]time.Time),
+		running:          make(map[string]bool),
+	}
+}
+
+// run checks for due sources on every tick until ctx is cancelled
+// it only returns once every in-flight load has finished
+func (s *scheduler) run(ctx context.Context) {
+	ticker := time.NewTicker(s.config.Tick)
+	defer ticker.Stop()
+
+	defer s.wg.Wait()
+
+	for {
+		s.runDue(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runDue starts a load for every source whose next run is due
+func (s *scheduler) runDue(ctx context.Context) {
+	sources, err := s.sourceRepository.FindAll(ctx)
+	if err != nil {
+		log.Printf("scheduler: failed to find sources. err: %v\n", err)
+		return
+	}
+
+	now := s.now()
+	s.forgetRemoved(sources)
+
+	for _, source := range sources {
+		// manual sources are only loaded from uploaded documents
+		if source.Manual || !s.acquire(source, now) {
+			continue
+		}
+
+		s.wg.Add(1)
+
+		go func(source model.Source) {
+			defer s.wg.Done()
+			defer s.release(source.ID)
+
+			s.load(ctx, source)
+		}(source)
+	}
+}
+
+// acquire returns true and marks the source as running if it's due
+func (s *scheduler) acquire(source model.Source, now time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.running[source.ID] {
+		return false
+	}
+
+	next, ok := s.nextRuns[source.ID]
+	if !ok && source.Status != nil && source.Status.NextRunAt != nil {
+		// resume the schedule persisted before a restart
+		next = *source.Status.NextRunAt
+	}
+
+	if next.After(now) {
+		s.nextRuns[source.ID] = next
+		return false
+	}
+
+	s.running[source.ID] = true
+
+	return true
+}
+
+// forgetRemoved drops the schedule of sources that are no longer registered
+func (s *scheduler) forgetRemoved(sources []model.Source) {
+	registered := make(map[string]bool, len(sources))
+	for _, source := range sources {
+		registered[source.ID] = true
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id := range s.nextRuns {
+		if !registered[id] {
+			delete(s.nextRuns, id)
+		}
+	}
+}
+
+func (s *scheduler) release(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.running, id)
+}
+
+func (s *scheduler) load(ctx context.Context, source model.Source) {
+	lastRunAt := s.now()
+
+	var lastError string
+
+	report, err := s.service.Load(ctx, model.LoadRequest{FeedURL: source.FeedURL})
+	if err != nil {
+		lastError = err.Error()
+	}
+
+	for _, sr := range report.Sources {
+		if sr.Error != "" {
+			lastError = sr.Error
+		}
+	}
+
+	if lastError != "" {
+		log.Printf("scheduler: failed to load source %s. err: %v\n", source.ID, lastError)
+	}
+
+	nextRunAt := s.now().Add(s.interval(source) + s.jitter())
+
+	s.mu.Lock()
+	s.nextRuns[source.ID] = nextRunAt
+	s.mu.Unlock()
+
+	// the schedule is still recorded when shutting down
+	if err := s.sourceRepository.UpdateSchedule(context.WithoutCancel(ctx), source.ID, lastRunAt, nextRunAt, lastError); err != nil {
+		log.Printf("scheduler: failed to update source %s schedule. err: %v\n", source.ID, err)
+	}
+}
+
+func (s *scheduler) interval(source model.Source) time.Duration {
+	if source.PollIntervalSeconds > 0 {
+		return time.Duration(source.PollIntervalSeconds) * time.Second
+	}
+
+	return s.config.Interval
+}
+
+func (s *scheduler) jitter() time.Duration {
+	if s.config.Jitter <= 0 {
+		return 0
+	}
+
+	// #nosec G404 -- jitter doesn't need a cryptographically secure random number
+	return rand.N(s.config.Jitter)
+}
@@ -0,0 +1,119 @@
+package news
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/suite"
+
+	"go-news-feed/pkg/model"
+)
+
+type SchedulerTestSuite struct {
+	suite.Suite
+	serviceMock          *MockService
+	sourceRepositoryMock *MockSourceRepository
+	scheduler            *scheduler
+	now                  time.Time
+}
+
+func (suite *SchedulerTestSuite) SetupTest() {
+	ctrl := gomock.NewController(suite.T())
+	suite.serviceMock = NewMockService(ctrl)
+	suite.sourceRepositoryMock = NewMockSourceRepository(ctrl)
+	suite.now = time.Date(2024, 5, 14, 10, 0, 0, 0, time.UTC)
+
+	suite.scheduler = newScheduler(suite.serviceMock, suite.sourceRepositoryMock, SchedulerConfig{
+		Interval: 15 * time.Minute,
+		Jitter:   time.Minute,
+		Tick:     time.Second,
+	})
+	suite.scheduler.now = func() time.Time { return suite.now }
+}
+
+func (suite *SchedulerTestSuite) TestRunDue() {
+	future := suite.now.Add(time.Hour)
+	past := suite.now.Add(-time.Minute)
+
+	testCases := []struct {
+		name             string
+		given            model.Source
+		loadErr          error
+		report           model.LoadReport
+		expectedLoad     bool
+		expectedInterval time.Duration
+		expectedError    string
+	}{
+		{
+			name:             "RunNeverRunSource",
+			given:            model.Source{ID: "1", FeedURL: "https://example.com/1.xml"},
+			expectedLoad:     true,
+			expectedInterval: 15 * time.Minute,
+		},
+		{
+			name:             "RunOverdueSourceWithOwnInterval",
+			given:            model.Source{ID: "2", FeedURL: "https://example.com/2.xml", PollIntervalSeconds: 300, Status: &model.SourceStatus{NextRunAt: &past}},
+			expectedLoad:     true,
+			expectedInterval: 5 * time.Minute,
+		},
+		{
+			name:             "RunFailingSource",
+			given:            model.Source{ID: "3", FeedURL: "https://example.com/3.xml"},
+			loadErr:          errors.New("feed unavailable"),
+			expectedLoad:     true,
+			expectedInterval: 15 * time.Minute,
+			expectedError:    "feed unavailable",
+		},
+		{
+			name:             "RunSourceReportingError",
+			given:            model.Source{ID: "5", FeedURL: "https://example.com/5.xml"},
+			report:           model.LoadReport{Sources: []model.SourceReport{{Error: "feed unavailable"}}},
+			expectedLoad:     true,
+			expectedInterval: 15 * time.Minute,
+			expectedError:    "feed unavailable",
+		},
+		{
+			name:  "SkipManualSource",
+			given: model.Source{ID: "6", FeedURL: "https://example.com/6.xml", Manual: true},
+		},
+		{
+			name:  "SkipNotDueSource",
+			given: model.Source{ID: "4", FeedURL: "https://example.com/4.xml", Status: &model.SourceStatus{NextRunAt: &future}},
+		},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			suite.sourceRepositoryMock.EXPECT().FindAll(gomock.Any()).Return([]model.Source{tc.given}, nil)
+
+			if tc.expectedLoad {
+				suite.serviceMock.EXPECT().Load(gomock.Any(), model.LoadRequest{FeedURL: tc.given.FeedURL}).Return(tc.report, tc.loadErr)
+				suite.sourceRepositoryMock.EXPECT().
+					UpdateSchedule(gomock.Any(), tc.given.ID, suite.now, gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, _ string, _, nextRunAt time.Time, lastError string) error {
+						suite.False(nextRunAt.Before(suite.now.Add(tc.expectedInterval)))
+						suite.True(nextRunAt.Before(suite.now.Add(tc.expectedInterval + time.Minute)))
+
+						suite.Equal(tc.expectedError, lastError)
+
+						return nil
+					})
+			}
+
+			suite.scheduler.runDue(context.Background())
+			suite.scheduler.wg.Wait()
+
+			// the next run is in the future so it isn't due again
+			suite.sourceRepositoryMock.EXPECT().FindAll(gomock.Any()).Return([]model.Source{tc.given}, nil)
+			suite.scheduler.runDue(context.Background())
+			suite.scheduler.wg.Wait()
+		})
+	}
+}
+
+func TestSchedulerTestSuite(t *testing.T) {
+	suite.Run(t, new(SchedulerTestSuite))
+}
@@ -2,15 +2,28 @@ package news
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
 	"time"
+
+	"github.com/go-playground/validator/v10"
+
+	"go-news-feed/pkg/model"
 )
 
 type Server struct {
-	mux    *http.ServeMux
-	config Config
+	mux       *http.ServeMux
+	config    Config
+	service   Service
+	scheduler *scheduler
+	jobs      *jobRunner
 }
 
 // NewServer - constructor
@@ -28,20 +41,111 @@ func (s *Server) Init() error {
 
 	s.config = config
 
-	repository, err := newRepository(ctx, config.MongoConfig)
+	db, err := newDatabase(ctx, config.MongoConfig)
+	if err != nil {
+		return err
+	}
+
+	sourceRepository, err := newSourceRepository(ctx, db, config.MongoConfig)
+	if err != nil {
+		return err
+	}
+
+	if err := seedSources(ctx, sourceRepository); err != nil {
+		return err
+	}
+
+	revisionRepository, err := newRevisionRepository(ctx, db, config.MongoConfig)
+	if err != nil {
+		return err
+	}
+
+	repository, err := newRepository(ctx, db, config.MongoConfig)
+	if err != nil {
+		return err
+	}
+
+	ruleRepository, err := newRuleRepository(ctx, db, config.MongoConfig)
+	if err != nil {
+		return err
+	}
+
+	quarantineRepository, err := newQuarantineRepository(ctx, db, config.MongoConfig)
+	if err != nil {
+		return err
+	}
+
+	jobRepository, err := newJobRepository(ctx, db, config.MongoConfig)
+	if err != nil {
+		return err
+	}
+
+	snapshotRepository, err := newSnapshotRepository(ctx, db, config.MongoConfig, config.Snapshots.Retention)
 	if err != nil {
 		return err
 	}
 
-	service := newService(repository)
-	endpoint := newEndpoint(service)
+	// the same validator checks the requests and the articles of the feeds
+	validate := validator.New()
+
+	service := newService(
+		repository,
+		sourceRepository,
+		revisionRepository,
+		ruleRepository,
+		quarantineRepository,
+		jobRepository,
+		snapshotRepository,
+		validate,
+		config,
+	)
+	endpoint := newEndpoint(service, validate)
+
+	s.service = service
 
 	s.mux = endpoint.init()
 
+	if config.Scheduler.Enabled {
+		s.scheduler = newScheduler(service, sourceRepository, config.Scheduler)
+	}
+
+	s.jobs = newJobRunner(service, jobRepository, config.Jobs)
+
 	return nil
 }
 
+// Start runs the HTTP server, the job runner and the scheduler (if enabled)
+// until an interrupt or terminate signal is received
 func (s *Server) Start() error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var wg sync.WaitGroup
+
+	// Start scheduler
+	if s.scheduler != nil {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			log.Println("scheduler started...")
+			s.scheduler.run(ctx)
+			log.Println("scheduler stopped")
+		}()
+	}
+
+	// Start job runner
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+
+		log.Println("job runner started...")
+		s.jobs.run(ctx)
+		log.Println("job runner stopped")
+	}()
+
 	// Start HTTP server
 	addr := fmt.Sprintf(":%d", s.config.Server.Port)
 	log.Printf("server listening on port %d...\n", s.config.Server.Port)
@@ -52,5 +156,48 @@ func (s *Server) Start() error {
 		Handler:           s.mux,
 	}
 
-	return server.ListenAndServe()
+	errCh := make(chan error, 1)
+
+	go func() {
+		errCh <- server.ListenAndServe()
+	}()
+
+	var err error
+
+	select {
+	case err = <-errCh:
+		stop()
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), s.config.Server.ShutdownTimeout)
+		defer cancel()
+
+		log.Println("shutting down server...")
+		err = server.Shutdown(shutdownCtx)
+	}
+
+	wg.Wait()
+
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+
+	return err
+}
+
+// Replay runs the ingestion of a feed snapshot again, see cmd/replay
+// the server must be initialised first but not started
+func (s *Server) Replay(ctx context.Context, id string, dryRun bool) (model.LoadReport, error) {
+	return s.service.ReplaySnapshot(ctx, id, dryRun)
+}
+
+// ExportSnapshot writes the body of a feed snapshot as it was fetched, e.g. to be used as a test fixture
+func (s *Server) ExportSnapshot(ctx context.Context, id string, w io.Writer) error {
+	_, body, err := s.service.FindSnapshot(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(body)
+
+	return err
 }
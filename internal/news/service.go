@@ -1,16 +1,33 @@
 package news
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"path"
+	"slices"
 	"sort"
 	"strings"
+	"sync"
+	"time"
 
+	"github.com/go-playground/validator/v10"
 	"github.com/mmcdole/gofeed"
-	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/net/publicsuffix"
 
 	"go-news-feed/pkg/model"
+	"go-news-feed/pkg/pipeline"
+)
+
+const (
+	defaultProvider = "unknown"
+	defaultCategory = "general"
 )
 
 // Service - interface
@@ -18,19 +35,79 @@ import (
 //go:generate mockgen -source=service.go -destination=service_mock.go --package=news
 type Service interface {
 	Find(ctx context.Context, sr model.FindRequest) (model.FindResponse, error)
-	Load(ctx context.Context, feedURL string) ([]model.Article, error)
+	Load(ctx context.Context, lr model.LoadRequest) (model.LoadReport, error)
+	LoadDocument(ctx context.Context, ur model.UploadRequest, document io.Reader) (model.LoadReport, error)
+	CreateSource(ctx context.Context, source model.Source) (model.Source, error)
+	FindSources(ctx context.Context) ([]model.Source, error)
+	FindSourceByID(ctx context.Context, id string) (model.Source, error)
+	UpdateSource(ctx context.Context, source model.Source) (model.Source, error)
+	DeleteSource(ctx context.Context, id string) error
+	FindSourceStatus(ctx context.Context, id string) (model.SourceStatus, error)
+	FindArticleByID(ctx context.Context, id string) (model.Article, error)
+	FindRevisions(ctx context.Context, articleID string) ([]model.RevisionDiff, error)
+	FindStory(ctx context.Context, id string) (model.Story, error)
+	CreateRule(ctx context.Context, rule model.Rule) (model.Rule, error)
+	FindRules(ctx context.Context, sourceID string) ([]model.Rule, error)
+	FindRuleByID(ctx context.Context, id string) (model.Rule, error)
+	UpdateRule(ctx context.Context, rule model.Rule) (model.Rule, error)
+	DeleteRule(ctx context.Context, id string) error
+	DryRunRule(ctx context.Context, rule model.Rule) (model.DryRunResponse, error)
+	FindQuarantine(ctx context.Context, qr model.QuarantineRequest) (model.QuarantineResponse, error)
+	FindQuarantinedItem(ctx context.Context, id string) (model.QuarantinedItem, error)
+	ReprocessQuarantinedItem(ctx context.Context, id string) (model.Article, error)
+	DiscardQuarantinedItem(ctx context.Context, id string) error
+	CreateLoadJob(ctx context.Context, lr model.LoadRequest) (model.Job, error)
+	FindLoadJob(ctx context.Context, id string) (model.Job, error)
+	FindLoadJobs(ctx context.Context, jr model.JobRequest) (model.JobResponse, error)
+	RunLoadJob(ctx context.Context, job model.Job) model.Job
+	FindSnapshots(ctx context.Context, sr model.SnapshotRequest) (model.SnapshotResponse, error)
+	FindSnapshot(ctx context.Context, id string) (model.Snapshot, []byte, error)
+	ReplaySnapshot(ctx context.Context, id string, dryRun bool) (model.LoadReport, error)
 }
 
 type service struct {
-	feedParser *gofeed.Parser
-	repository Repository
+	config               Config
+	fetcher              *fetcher
+	extractor            *extractor
+	stages               *pipeline.Registry
+	repository           Repository
+	sourceRepository     SourceRepository
+	revisionRepository   RevisionRepository
+	ruleRepository       RuleRepository
+	quarantineRepository QuarantineRepository
+	jobRepository        JobRepository
+	snapshotRepository   SnapshotRepository
+	validator            *validator.Validate
 }
 
 // newService - constructor
-func newService(repository Repository) Service {
+func newService(
+	repository Repository,
+	sourceRepository SourceRepository,
+	revisionRepository RevisionRepository,
+	ruleRepository RuleRepository,
+	quarantineRepository QuarantineRepository,
+	jobRepository JobRepository,
+	snapshotRepository SnapshotRepository,
+	validator *validator.Validate,
+	config Config,
+) Service {
+	// the feed and the extraction fetches share the limits of their hosts
+	limiter := newHostLimiter(config.Fetcher.HostInterval, config.Fetcher.HostConcurrency)
+
 	return &service{
-		feedParser: gofeed.NewParser(),
-		repository: repository,
+		config:               config,
+		fetcher:              newFetcher(config.Fetcher, limiter),
+		extractor:            newExtractor(config.Extractor, limiter),
+		stages:               pipeline.DefaultRegistry,
+		repository:           repository,
+		sourceRepository:     sourceRepository,
+		revisionRepository:   revisionRepository,
+		ruleRepository:       ruleRepository,
+		quarantineRepository: quarantineRepository,
+		jobRepository:        jobRepository,
+		snapshotRepository:   snapshotRepository,
+		validator:            validator,
 	}
 }
 
@@ -38,144 +115,1224 @@ func (s *service) Find(ctx context.Context, sr model.FindRequest) (model.FindRes
 	return s.repository.Find(ctx, sr)
 }
 
-func (s *service) Load(ctx context.Context, feedURL string) ([]model.Article, error) {
-	articles, err := s.loadArticlesFromFeed(ctx, feedURL)
+func (s *service) CreateSource(ctx context.Context, source model.Source) (model.Source, error) {
+	if err := s.validateSource(source); err != nil {
+		return model.Source{}, err
+	}
+
+	source.ID = newID()
+	source.Status = nil
+
+	if err := s.sourceRepository.Create(ctx, source); err != nil {
+		return model.Source{}, err
+	}
+
+	return source, nil
+}
+
+// validateSource checks the settings of the source the validator can't check
+// i.e. the link policy patterns, the pipeline stages and the http settings
+func (s *service) validateSource(source model.Source) error {
+	if err := validateLinkPolicy(source.LinkPolicy); err != nil {
+		return err
+	}
+
+	if err := validateHTTPSettings(source.HTTP, s.config.Fetcher.SecretPrefix); err != nil {
+		return err
+	}
+
+	if _, err := s.stages.Build(source.Pipeline); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidRequest, err)
+	}
+
+	return nil
+}
+
+func (s *service) FindSources(ctx context.Context) ([]model.Source, error) {
+	return s.sourceRepository.FindAll(ctx)
+}
+
+func (s *service) FindSourceByID(ctx context.Context, id string) (model.Source, error) {
+	return s.sourceRepository.FindByID(ctx, id)
+}
+
+func (s *service) UpdateSource(ctx context.Context, source model.Source) (model.Source, error) {
+	if err := s.validateSource(source); err != nil {
+		return model.Source{}, err
+	}
+
+	existing, err := s.sourceRepository.FindByID(ctx, source.ID)
+	if err != nil {
+		return model.Source{}, err
+	}
+
+	// status is maintained by the server, it's left untouched by the update
+	source.Status = existing.Status
+
+	if err := s.sourceRepository.Update(ctx, source); err != nil {
+		return model.Source{}, err
+	}
+
+	return source, nil
+}
+
+func (s *service) DeleteSource(ctx context.Context, id string) error {
+	return s.sourceRepository.Delete(ctx, id)
+}
+
+func (s *service) FindSourceStatus(ctx context.Context, id string) (model.SourceStatus, error) {
+	source, err := s.sourceRepository.FindByID(ctx, id)
+	if err != nil {
+		return model.SourceStatus{}, err
+	}
+
+	var status model.SourceStatus
+	if source.Status != nil {
+		status = *source.Status
+	}
+
+	circuit := s.fetcher.status(source.FeedURL)
+	status.Circuit = &circuit
+
+	host := s.fetcher.hostStatus(source.FeedURL)
+	status.Host = &host
+
+	return status, nil
+}
+
+func (s *service) FindArticleByID(ctx context.Context, id string) (model.Article, error) {
+	return s.repository.FindByID(ctx, id)
+}
+
+// FindRevisions returns the changes between every revision of an article
+// from the oldest revision to the current version of the article
+func (s *service) FindRevisions(ctx context.Context, articleID string) ([]model.RevisionDiff, error) {
+	article, err := s.repository.FindByID(ctx, articleID)
 	if err != nil {
 		return nil, err
 	}
 
-	if err := s.saveArticles(ctx, articles); err != nil {
+	revisions, err := s.revisionRepository.FindByArticleID(ctx, articleID)
+	if err != nil {
 		return nil, err
 	}
 
-	return articles, nil
+	revisions = append(revisions, model.NewRevision(article, time.Time{}))
+
+	diffs := make([]model.RevisionDiff, 0, len(revisions)-1)
+	for i := 0; i < len(revisions)-1; i++ {
+		diffs = append(diffs, revisions[i].Diff(revisions[i+1]))
+	}
+
+	return diffs, nil
 }
 
-// loadArticlesFromFeed and convert to an article slice ordered by published time (asc)
-func (s *service) loadArticlesFromFeed(ctx context.Context, feedURL string) ([]model.Article, error) {
-	var articles = make(model.Articles, 0)
-	sources := s.getSources(feedURL)
+// FindStory returns every version of a story ordered by published time (asc)
+func (s *service) FindStory(ctx context.Context, id string) (model.Story, error) {
+	articles, err := s.repository.FindByStoryID(ctx, id)
+	if err != nil {
+		return model.Story{}, err
+	}
+
+	if len(articles) == 0 {
+		return model.Story{}, ErrNotFound
+	}
+
+	story := model.Story{
+		ID:        id,
+		Title:     articles[0].Title,
+		Providers: make([]string, 0),
+		Articles:  articles,
+	}
+
+	providers := make(map[string]bool)
+	for _, article := range articles {
+		if provider := article.Source.Provider; !providers[provider] {
+			providers[provider] = true
+			story.Providers = append(story.Providers, provider)
+		}
+	}
+
+	return story, nil
+}
+
+func (s *service) CreateRule(ctx context.Context, rule model.Rule) (model.Rule, error) {
+	compiled, err := compileRule(rule)
+	if err != nil {
+		return model.Rule{}, err
+	}
+
+	rule = compiled.Rule
+	rule.ID = newID()
+
+	if err := s.ruleRepository.Create(ctx, rule); err != nil {
+		return model.Rule{}, err
+	}
+
+	return rule, nil
+}
+
+// FindRules returns every rule, or the rules applied to the source
+// i.e. the global rules and the rules of the source
+func (s *service) FindRules(ctx context.Context, sourceID string) ([]model.Rule, error) {
+	rules, err := s.ruleRepository.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if sourceID == "" {
+		return rules, nil
+	}
+
+	applied := make([]model.Rule, 0, len(rules))
+	for _, rule := range rules {
+		if rule.SourceID == "" || rule.SourceID == sourceID {
+			applied = append(applied, rule)
+		}
+	}
+
+	return applied, nil
+}
+
+func (s *service) FindRuleByID(ctx context.Context, id string) (model.Rule, error) {
+	return s.ruleRepository.FindByID(ctx, id)
+}
+
+func (s *service) UpdateRule(ctx context.Context, rule model.Rule) (model.Rule, error) {
+	compiled, err := compileRule(rule)
+	if err != nil {
+		return model.Rule{}, err
+	}
+
+	if err := s.ruleRepository.Update(ctx, compiled.Rule); err != nil {
+		return model.Rule{}, err
+	}
+
+	return compiled.Rule, nil
+}
+
+func (s *service) DeleteRule(ctx context.Context, id string) error {
+	return s.ruleRepository.Delete(ctx, id)
+}
+
+// DryRunRule returns the latest stored articles (of the source of the rule) matching the rule
+// nothing is changed, so a rule can be checked before it is created
+func (s *service) DryRunRule(ctx context.Context, rule model.Rule) (model.DryRunResponse, error) {
+	compiled, err := compileRule(rule)
+	if err != nil {
+		return model.DryRunResponse{}, err
+	}
+
+	articles, err := s.repository.FindRecent(ctx, rule.SourceID, maxLimit)
+	if err != nil {
+		return model.DryRunResponse{}, err
+	}
+
+	response := model.DryRunResponse{
+		Rule:     compiled.Rule,
+		Scanned:  len(articles),
+		Articles: make([]model.Article, 0),
+	}
+
+	now := time.Now().UTC()
+	for _, article := range articles {
+		if compiled.matches(article, now) {
+			response.Articles = append(response.Articles, article)
+		}
+	}
+
+	response.Matched = len(response.Articles)
+
+	return response, nil
+}
+
+// FindQuarantine returns the quarantined items, latest first
+func (s *service) FindQuarantine(ctx context.Context, qr model.QuarantineRequest) (model.QuarantineResponse, error) {
+	return s.quarantineRepository.Find(ctx, qr)
+}
+
+func (s *service) FindQuarantinedItem(ctx context.Context, id string) (model.QuarantinedItem, error) {
+	return s.quarantineRepository.FindByID(ctx, id)
+}
+
+// ReprocessQuarantinedItem runs a quarantined item through the validation and the rules again
+// with the current settings of its source, and saves it once accepted
+// an item still rejected stays in quarantine with the new reason
+func (s *service) ReprocessQuarantinedItem(ctx context.Context, id string) (model.Article, error) {
+	item, err := s.quarantineRepository.FindByID(ctx, id)
+	if err != nil {
+		return model.Article{}, err
+	}
+
+	source, err := s.sourceRepository.FindByID(ctx, item.Source.ID)
+	if err != nil {
+		return model.Article{}, fmt.Errorf("source %s: %w", item.Source.ID, err)
+	}
+
+	article, reason, err := s.reprocessItem(ctx, source, item)
+	if err != nil {
+		return model.Article{}, err
+	}
+
+	if reason != "" {
+		item.Article = article
+		item.Reason = reason
+
+		if err := s.quarantineRepository.Update(ctx, item); err != nil {
+			return model.Article{}, err
+		}
+
+		return model.Article{}, fmt.Errorf("%w: still quarantined: %s", ErrInvalidRequest, reason)
+	}
+
+	if _, err := s.saveArticles(ctx, source, []model.Article{article}); err != nil {
+		return model.Article{}, err
+	}
+
+	if err := s.quarantineRepository.Delete(ctx, id); err != nil {
+		return model.Article{}, err
+	}
+
+	return article, nil
+}
+
+// DiscardQuarantinedItem removes an item from the quarantine for good
+// it's kept as discarded so the next loads don't quarantine the article again
+func (s *service) DiscardQuarantinedItem(ctx context.Context, id string) error {
+	return s.quarantineRepository.Discard(ctx, id, time.Now().UTC())
+}
+
+// CreateLoadJob queues the load of a feed url (or every registered source)
+// the job is run in the background by the job runner
+func (s *service) CreateLoadJob(ctx context.Context, lr model.LoadRequest) (model.Job, error) {
+	if lr.FeedURL != "" && !isFeedURL(lr.FeedURL) {
+		return model.Job{}, fmt.Errorf("%w: %s is not a valid http(s) url", ErrInvalidFeed, lr.FeedURL)
+	}
+
+	job := model.Job{
+		ID:        newID(),
+		FeedURL:   lr.FeedURL,
+		DryRun:    lr.DryRun,
+		Status:    model.JobQueued,
+		CreatedAt: time.Now().UTC(),
+	}
+
+	if err := s.jobRepository.Create(ctx, job); err != nil {
+		return model.Job{}, err
+	}
+
+	return job, nil
+}
+
+func (s *service) FindLoadJob(ctx context.Context, id string) (model.Job, error) {
+	return s.jobRepository.FindByID(ctx, id)
+}
+
+// FindLoadJobs returns the history of the load jobs, latest first
+func (s *service) FindLoadJobs(ctx context.Context, jr model.JobRequest) (model.JobResponse, error) {
+	return s.jobRepository.Find(ctx, jr)
+}
+
+// RunLoadJob loads the feed of a claimed job within the configured job timeout
+// the progress and the report of the job are recorded every time a source is saved
+// it returns the job finished
+func (s *service) RunLoadJob(ctx context.Context, job model.Job) model.Job {
+	ctx, cancel := context.WithTimeout(ctx, s.config.Jobs.Timeout)
+	defer cancel()
+
+	lr := model.LoadRequest{FeedURL: job.FeedURL, DryRun: job.DryRun}
+
+	report, err := s.load(ctx, lr, func(report model.LoadReport, sources int) {
+		job.Progress = model.JobProgress{Sources: sources, Done: len(report.Sources)}
+		job.Report = &report
+
+		err := s.jobRepository.UpdateProgress(ctx, job)
+		if errors.Is(err, ErrNotFound) {
+			// the job has been failed by another instance in the meantime
+			log.Printf("job %s is no longer held, cancelling it\n", job.ID)
+			cancel()
+
+			return
+		}
 
-	for _, source := range sources {
-		feed, err := s.feedParser.ParseURLWithContext(source.FeedURL, ctx)
 		if err != nil {
-			return nil, err
+			log.Printf("failed to update job %s progress: %v\n", job.ID, err)
+		}
+	})
+
+	finishedAt := time.Now().UTC()
+	job.FinishedAt = &finishedAt
+
+	if err != nil {
+		job.Status = model.JobFailed
+		job.Error = err.Error()
+	} else {
+		job.Status = model.JobSucceeded
+		job.Report = &report
+	}
+
+	// the outcome is still recorded when the job is cancelled, unless another instance has failed it
+	if err := s.jobRepository.Finish(context.WithoutCancel(ctx), job); err != nil {
+		log.Printf("failed to update job %s: %v\n", job.ID, err)
+	}
+
+	return job
+}
+
+// FindSnapshots returns the snapshots of the fetched feeds without their body, latest first
+func (s *service) FindSnapshots(ctx context.Context, sr model.SnapshotRequest) (model.SnapshotResponse, error) {
+	return s.snapshotRepository.Find(ctx, sr)
+}
+
+// FindSnapshot returns the snapshot along with its body decompressed
+func (s *service) FindSnapshot(ctx context.Context, id string) (model.Snapshot, []byte, error) {
+	snapshot, err := s.snapshotRepository.FindByID(ctx, id)
+	if err != nil {
+		return model.Snapshot{}, nil, err
+	}
+
+	body, err := decompress(snapshot.Body)
+	if err != nil {
+		return model.Snapshot{}, nil, err
+	}
+
+	return snapshot, body, nil
+}
+
+// ReplaySnapshot runs the ingestion of a snapshot again as if it had just been fetched
+// with the current settings of its source, the rules and the stored articles
+// the validators of the source are left untouched
+func (s *service) ReplaySnapshot(ctx context.Context, id string, dryRun bool) (model.LoadReport, error) {
+	report := model.LoadReport{StartedAt: time.Now().UTC(), DryRun: dryRun}
+
+	snapshot, body, err := s.FindSnapshot(ctx, id)
+	if err != nil {
+		return model.LoadReport{}, err
+	}
+
+	source, err := s.snapshotSource(ctx, snapshot)
+	if err != nil {
+		return model.LoadReport{}, err
+	}
+
+	contentType := http.Header(snapshot.Header).Get("Content-Type")
+
+	return s.loadDocument(ctx, report, source, bytes.NewReader(body), contentType, snapshot.FetchedAt)
+}
+
+// Load fetches and saves the articles of every source requested
+// failing sources are reported without stopping the healthy ones
+// a dry run goes through the same checks but only reports what would be written
+func (s *service) Load(ctx context.Context, lr model.LoadRequest) (model.LoadReport, error) {
+	return s.load(ctx, lr, nil)
+}
+
+// LoadDocument saves the articles of a feed document uploaded rather than fetched
+// e.g. a feed only reachable from a private network
+// it goes through the same checks as a fetched feed, dry runs included
+func (s *service) LoadDocument(ctx context.Context, ur model.UploadRequest, document io.Reader) (model.LoadReport, error) {
+	report := model.LoadReport{StartedAt: time.Now().UTC(), DryRun: ur.DryRun}
+
+	if ur.SourceID == "" && !isFeedURL(ur.FeedURL) {
+		return model.LoadReport{}, fmt.Errorf("%w: a source id or an http(s) feed url is required", ErrInvalidRequest)
+	}
+
+	source, err := s.uploadSource(ctx, ur)
+	if err != nil {
+		return model.LoadReport{}, err
+	}
+
+	return s.loadDocument(ctx, report, source, document, ur.ContentType, time.Now().UTC())
+}
+
+// loadDocument saves the articles of a feed document uploaded or replayed
+// as if it had been fetched at the time provided
+func (s *service) loadDocument(ctx context.Context, report model.LoadReport, source model.Source, document io.Reader, contentType string, fetchedAt time.Time) (model.LoadReport, error) {
+	feed, err := s.fetcher.parse(document, contentType)
+	if err != nil {
+		return model.LoadReport{}, fmt.Errorf("%w: %v", ErrInvalidFeed, err)
+	}
+
+	result := s.processFeed(ctx, source, fetchResult{feed: feed, fetchedAt: fetchedAt}, report.DryRun)
+	result.uploaded = true
+
+	return s.saveResults(ctx, report, []sourceResult{result}, nil)
+}
+
+// uploadSource returns the registered source of an uploaded document
+// a feed url that isn't registered is recorded as a manual ad-hoc source once the document is parsed
+func (s *service) uploadSource(ctx context.Context, ur model.UploadRequest) (model.Source, error) {
+	if ur.SourceID != "" {
+		return s.sourceRepository.FindByID(ctx, ur.SourceID)
+	}
+
+	source, err := s.sourceRepository.FindByFeedURL(ctx, ur.FeedURL)
+	if !errors.Is(err, ErrNotFound) {
+		return source, err
+	}
+
+	return model.Source{FeedURL: ur.FeedURL, Category: ur.Category, Provider: ur.Provider, Manual: true}, nil
+}
+
+// load is Load reporting its progress, if a progress func is provided
+// it's called with the report so far and the number of sources loaded
+// once the feeds are fetched and every time a source is saved
+func (s *service) load(ctx context.Context, lr model.LoadRequest, progress func(report model.LoadReport, sources int)) (model.LoadReport, error) {
+	report := model.LoadReport{StartedAt: time.Now().UTC(), DryRun: lr.DryRun}
+
+	results, err := s.loadArticlesFromFeed(ctx, lr)
+	if err != nil {
+		return model.LoadReport{}, err
+	}
+
+	return s.saveResults(ctx, report, results, progress)
+}
+
+// saveResults saves the articles of the sources loaded and completes the report
+// the rules are applied first, the articles they reject are dropped or quarantined
+func (s *service) saveResults(ctx context.Context, report model.LoadReport, results []sourceResult, progress func(report model.LoadReport, sources int)) (model.LoadReport, error) {
+	if progress != nil {
+		progress(report, len(results))
+	}
+
+	stored, err := s.ruleRepository.FindAll(ctx)
+	if err != nil {
+		return model.LoadReport{}, err
+	}
+
+	rules, err := compileRules(stored)
+	if err != nil {
+		return model.LoadReport{}, err
+	}
+
+	for _, result := range results {
+		sr := model.SourceReport{
+			Source:      result.source.Reference(),
+			Fetched:     result.fetched,
+			Filtered:    result.filtered,
+			NotModified: result.notModified,
+		}
+
+		if result.err == nil && !result.notModified {
+			articles, rejected, dropped := applyRules(rules, result.source, result.articles, time.Now().UTC())
+
+			// the articles discarded from the quarantine before are dropped rather than quarantined again
+			quarantined, discarded, err := s.skipDiscarded(ctx, slices.Concat(result.quarantined, rejected))
+			dropped = slices.Concat(dropped, discarded)
+			sr.Filtered += len(dropped)
+			sr.Quarantined = len(quarantined)
+
+			switch {
+			case err != nil:
+				result.err = err
+			case report.DryRun:
+				var plan savePlan
+				if plan, result.err = s.planSave(ctx, articles); result.err == nil {
+					sr.New, sr.Updated, sr.Skipped = len(plan.creates), len(plan.updates), plan.skipped
+					sr.Items = dryRunItems(quarantined, slices.Concat(result.dropped, dropped), plan.outcomes)
+				}
+			case len(quarantined) > 0:
+				result.err = s.quarantineRepository.CreateMany(ctx, quarantined)
+			}
+
+			if result.err == nil && !report.DryRun {
+				var saved saveResult
+				if saved, result.err = s.saveArticles(ctx, result.source, articles); result.err == nil {
+					sr.New, sr.Updated, sr.Skipped, sr.Conflicts = saved.created, saved.updated, saved.skipped, saved.conflicts
+				}
+			}
 		}
 
-		result, err := s.parseFeed(feed)
+		// validators are only recorded once the articles are saved
+		// otherwise the next fetch would skip them as not modified
+		// uploaded documents have none
+		if result.err == nil && !report.DryRun && !result.uploaded {
+			result.err = s.updateFetchState(ctx, result)
+		}
+
+		if result.err != nil {
+			sr.Error = result.err.Error()
+		}
+
+		report.Add(sr)
+
+		if progress != nil {
+			progress(report, len(results))
+		}
+	}
+
+	report.FinishedAt = time.Now().UTC()
+
+	return report, nil
+}
+
+// loadArticlesFromFeed and convert to article slices ordered by published time (asc)
+// sources are fetched concurrently by a bounded pool of workers
+func (s *service) loadArticlesFromFeed(ctx context.Context, lr model.LoadRequest) ([]sourceResult, error) {
+	sources, err := s.getSources(ctx, lr.FeedURL)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]sourceResult, len(sources))
+	indexes := make(chan int)
+
+	var wg sync.WaitGroup
+
+	for range max(1, min(s.config.Fetcher.Workers, len(sources))) {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for i := range indexes {
+				results[i] = s.loadSource(ctx, sources[i], lr.DryRun)
+			}
+		}()
+	}
+
+	for i := range sources {
+		indexes <- i
+	}
+
+	close(indexes)
+	wg.Wait()
+
+	// the request has been cancelled
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// an unregistered feed that can't be loaded is reported as an invalid request
+	if len(sources) == 1 && sources[0].ID == "" && results[0].err != nil {
+		return nil, results[0].err
+	}
+
+	return results, nil
+}
+
+// sourceResult is the outcome of loading a single source
+type sourceResult struct {
+	fetchResult
+	source   model.Source
+	articles []model.Article
+	// quarantined are the articles failing validation
+	quarantined []model.QuarantinedItem
+	// dropped are the articles dropped by the pipeline, only listed on dry runs
+	dropped []model.ItemOutcome
+	// uploaded is set when the feed has been uploaded or replayed rather than fetched
+	uploaded bool
+	// fetched is the number of articles of the feed
+	// filtered is the number of articles dropped by the pipeline
+	fetched  int
+	filtered int
+	err      error
+}
+
+// loadSource fetches and parses the feed of a single source
+// within the configured per source timeout
+func (s *service) loadSource(ctx context.Context, source model.Source, dryRun bool) sourceResult {
+	ctx, cancel := context.WithTimeout(ctx, s.config.Fetcher.Timeout)
+	defer cancel()
+
+	fr, err := s.fetcher.fetch(ctx, source)
+
+	// the body is archived even if it can't be parsed
+	if fr.body != nil && !dryRun {
+		s.saveSnapshot(ctx, source, fr, err)
+	}
+
+	if err != nil {
+		if source.ID == "" {
+			err = fmt.Errorf("%w: %s: %v", ErrInvalidFeed, source.FeedURL, err)
+		}
+
+		return sourceResult{source: source, err: err}
+	}
+
+	if fr.notModified {
+		return sourceResult{fetchResult: fr, source: source}
+	}
+
+	return s.processFeed(ctx, source, fr, dryRun)
+}
+
+// processFeed parses and validates the feed of a source, fetched or uploaded
+// and runs the articles through the pipeline of the source
+func (s *service) processFeed(ctx context.Context, source model.Source, fr fetchResult, dryRun bool) sourceResult {
+	var err error
+
+	// sources without id are not registered yet
+	// so they are recorded as ad-hoc sources once the feed is known to be valid
+	// a dry run only derives the source without recording it
+	switch {
+	case source.ID == "" && dryRun:
+		source = newAdHocSource(source, fr.feed)
+	case source.ID == "":
+		source, err = s.createAdHocSource(ctx, source, fr.feed)
 		if err != nil {
-			return nil, err
+			return sourceResult{source: source, err: err}
 		}
+	}
+
+	articles, err := s.parseFeed(fr.feed, source, fr.fetchedAt)
+	if err != nil {
+		return sourceResult{source: source, err: err}
+	}
+
+	fetched := len(articles)
+
+	articles, quarantined := s.validateArticles(source, fr.feed.Items, articles, fr.fetchedAt)
+	valid := len(articles)
+
+	p, err := s.stages.Build(source.Pipeline)
+	if err != nil {
+		return sourceResult{source: source, err: err}
+	}
+
+	processed, err := p.Run(ctx, source, articles)
+	if err != nil {
+		return sourceResult{source: source, err: err}
+	}
 
-		articles = append(articles, result...)
+	var dropped []model.ItemOutcome
+	if dryRun {
+		dropped = droppedBy(articles, processed)
 	}
 
+	articles = processed
+
 	// could use sort from gofeed.Feed model
 	// but adding in the article
 	// just for the sake of an example
 	// of how to sort a custom slice
-	sort.Sort(articles)
+	sort.Sort(model.Articles(articles))
 
-	return articles, nil
+	return sourceResult{
+		fetchResult: fr,
+		source:      source,
+		articles:    articles,
+		quarantined: quarantined,
+		dropped:     dropped,
+		fetched:     fetched,
+		filtered:    max(0, valid-len(articles)),
+	}
 }
 
-// saveArticles persists new articles
-func (s *service) saveArticles(ctx context.Context, articles []model.Article) error {
+// updateFetchState records the validators of the feed when they have changed
+func (s *service) updateFetchState(ctx context.Context, result sourceResult) error {
+	status := result.source.Status
+	if status == nil {
+		status = &model.SourceStatus{}
+	}
+
+	if status.ETag == result.etag && status.LastModified == result.lastModified {
+		return nil
+	}
+
+	return s.sourceRepository.UpdateFetchState(ctx, result.source.ID, result.etag, result.lastModified)
+}
+
+// savePlan is what saving the articles of a source would write
+type savePlan struct {
+	creates   []model.Article
+	updates   []ArticleUpdate
+	revisions []model.Revision
+	// skipped is the number of duplicates and unchanged articles
+	skipped int
+	// outcomes lists what is done with every article and why, in order
+	outcomes []model.ItemOutcome
+}
+
+// planSave compares the articles with the stored ones without writing anything
+// new articles are created, changed ones are updated keeping their previous version
+// and duplicates within the feed or unchanged articles are skipped
+func (s *service) planSave(ctx context.Context, articles []model.Article) (savePlan, error) {
+	plan := savePlan{
+		creates:   make([]model.Article, 0),
+		updates:   make([]ArticleUpdate, 0),
+		revisions: make([]model.Revision, 0),
+		outcomes:  make([]model.ItemOutcome, 0, len(articles)),
+	}
+
+	if len(articles) == 0 {
+		return plan, nil
+	}
+
+	ids := make([]string, len(articles))
+	for i, article := range articles {
+		ids[i] = article.ID
+	}
+
+	stored, err := s.repository.FindByIDs(ctx, ids)
+	if err != nil {
+		return savePlan{}, err
+	}
+
+	existing := make(map[string]model.Article, len(stored))
+	for _, article := range stored {
+		existing[article.ID] = article
+	}
+
+	now := time.Now().UTC()
+
+	// the same story can be reached by two urls within the same feed
+	links := make(map[string]string, len(articles))
+
 	for _, article := range articles {
-		if err := s.validateArticle(ctx, article); err != nil {
+		if id, ok := links[article.CanonicalLink]; ok && id != article.ID {
+			plan.skipped++
+			plan.outcomes = append(plan.outcomes, model.NewItemOutcome(article, model.OutcomeSkip, fmt.Sprintf("duplicate of %s within the feed", id)))
 			continue
 		}
 
-		if err := s.repository.Create(ctx, article); err != nil {
-			return err
+		if article.CanonicalLink != "" {
+			links[article.CanonicalLink] = article.ID
 		}
+
+		previous, ok := existing[article.ID]
+		if !ok {
+			plan.creates = append(plan.creates, article)
+			plan.outcomes = append(plan.outcomes, model.NewItemOutcome(article, model.OutcomeCreate, "new article"))
+			continue
+		}
+
+		if !isArticleUpdated(previous, article) {
+			plan.skipped++
+			plan.outcomes = append(plan.outcomes, model.NewItemOutcome(article, model.OutcomeSkip, fmt.Sprintf("unchanged since revision %d", previous.Revision)))
+			continue
+		}
+
+		plan.outcomes = append(plan.outcomes, model.NewItemOutcome(article, model.OutcomeUpdate, updateReason(previous, article)))
+
+		article.Revision = previous.Revision + 1
+		article.StoryID = previous.StoryID
+
+		// an inferred published date would move on every fetch
+		if slices.Contains(article.InferredFields, inferredPublishedDateTime) && previous.PublishedDateTime != nil {
+			article.PublishedDateTime = previous.PublishedDateTime
+		}
+
+		// the linked page is only extracted again if the link has changed
+		if previous.CanonicalLink == article.CanonicalLink {
+			article.Content = previous.Content
+			article.WordCount = previous.WordCount
+			article.ReadingTimeMinutes = previous.ReadingTimeMinutes
+			article.LeadImage = previous.LeadImage
+		}
+		plan.updates = append(plan.updates, ArticleUpdate{Article: article, PreviousRevision: previous.Revision})
+		plan.revisions = append(plan.revisions, model.NewRevision(previous, now))
+
+		// the same article can't be updated twice from the same batch
+		existing[article.ID] = article
 	}
 
-	return nil
+	return plan, nil
+}
+
+// saveResult is what saving the articles of a source has written
+type saveResult struct {
+	created int
+	updated int
+	// skipped is the number of duplicates and unchanged articles
+	skipped int
+	// conflicts is the number of updates not applied
+	// as a concurrent load has updated the article first
+	conflicts int
 }
 
-// validateArticle checks if article exists already
-func (s *service) validateArticle(ctx context.Context, article model.Article) error {
-	tempArticle, err := s.repository.FindByID(ctx, article.ID)
+// saveArticles persists new articles and updates the changed ones
+// keeping their previous version as a revision
+func (s *service) saveArticles(ctx context.Context, source model.Source, articles []model.Article) (saveResult, error) {
+	if len(articles) == 0 {
+		return saveResult{}, nil
+	}
+
+	plan, err := s.planSave(ctx, articles)
 	if err != nil {
-		// if error is no documents found it means
-		// document is valid to be created
-		if err == mongo.ErrNoDocuments {
-			return nil
+		return saveResult{}, err
+	}
+
+	creates, updates := plan.creates, plan.updates
+
+	if source.Extract {
+		targets := make([]*model.Article, 0, len(creates)+len(updates))
+		for i := range creates {
+			targets = append(targets, &creates[i])
 		}
 
-		return err
+		for i := range updates {
+			if updates[i].Article.Content == "" {
+				targets = append(targets, &updates[i].Article)
+			}
+		}
+
+		s.extractArticles(ctx, targets)
 	}
 
-	if tempArticle.ID != "" {
-		return fmt.Errorf("article id %s exists already", tempArticle.ID)
+	if err := s.assignStories(ctx, creates); err != nil {
+		return saveResult{}, err
 	}
 
-	return nil
+	result, err := s.repository.BulkUpsert(ctx, creates)
+	if err != nil {
+		return saveResult{}, err
+	}
+
+	updated, err := s.repository.BulkUpdate(ctx, updates)
+	if err != nil {
+		return saveResult{}, err
+	}
+
+	revisions := plan.revisions
+	if updated < len(updates) {
+		if revisions, err = s.appliedRevisions(ctx, plan); err != nil {
+			return saveResult{}, err
+		}
+	}
+
+	// revisions are only stored for the updates applied
+	// an update lost to a concurrent load doesn't leave a revision behind
+	if err := s.revisionRepository.CreateMany(ctx, revisions); err != nil {
+		return saveResult{}, err
+	}
+
+	return saveResult{
+		created:   result.Created,
+		updated:   updated,
+		skipped:   plan.skipped + result.Existing,
+		conflicts: len(updates) - updated,
+	}, nil
+}
+
+// appliedRevisions returns the revisions of the updates of the plan that have been applied
+// i.e. the articles stored are the ones of the updates
+func (s *service) appliedRevisions(ctx context.Context, plan savePlan) ([]model.Revision, error) {
+	ids := make([]string, len(plan.updates))
+	for i, update := range plan.updates {
+		ids[i] = update.Article.ID
+	}
+
+	stored, err := s.repository.FindByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	current := make(map[string]model.Article, len(stored))
+	for _, article := range stored {
+		current[article.ID] = article
+	}
+
+	revisions := make([]model.Revision, 0, len(plan.revisions))
+
+	for i, update := range plan.updates {
+		article, ok := current[update.Article.ID]
+		if ok && article.Revision >= update.Article.Revision && article.ContentHash == update.Article.ContentHash {
+			revisions = append(revisions, plan.revisions[i])
+		}
+	}
+
+	return revisions, nil
+}
+
+// updateReason lists the fields of the article changed since it was stored
+func updateReason(previous, article model.Article) string {
+	diff := model.NewRevision(previous, time.Time{}).Diff(model.NewRevision(article, time.Time{}))
+
+	fields := make([]string, 0, len(diff.Changes))
+	for _, change := range diff.Changes {
+		fields = append(fields, change.Field)
+	}
+
+	if len(fields) == 0 {
+		return "newer updated date"
+	}
+
+	return strings.Join(fields, ", ") + " changed"
+}
+
+// droppedReason is why an article isn't saved when the pipeline of its source drops it
+const droppedReason = "dropped by the pipeline"
+
+// droppedBy returns the articles the pipeline has dropped
+func droppedBy(articles, processed []model.Article) []model.ItemOutcome {
+	kept := make(map[string]bool, len(processed))
+	for _, article := range processed {
+		kept[article.ID] = true
+	}
+
+	dropped := make([]model.ItemOutcome, 0)
+	for _, article := range articles {
+		if !kept[article.ID] {
+			dropped = append(dropped, model.NewItemOutcome(article, model.OutcomeFilter, droppedReason))
+		}
+	}
+
+	return dropped
+}
+
+// dryRunItems lists what a load would do with every article of a source
+func dryRunItems(quarantined []model.QuarantinedItem, dropped, saved []model.ItemOutcome) []model.ItemOutcome {
+	items := make([]model.ItemOutcome, 0, len(quarantined)+len(dropped)+len(saved))
+
+	for _, item := range quarantined {
+		items = append(items, model.NewItemOutcome(item.Article, model.OutcomeQuarantine, item.Reason))
+	}
+
+	items = append(items, dropped...)
+
+	return append(items, saved...)
+}
+
+// extractArticles sets the body of the articles extracted from their linked page
+// up to the configured max number of articles, concurrently
+// articles whose page can't be extracted are saved without body
+func (s *service) extractArticles(ctx context.Context, articles []*model.Article) {
+	if len(articles) > s.config.Extractor.MaxArticles {
+		articles = articles[:s.config.Extractor.MaxArticles]
+	}
+
+	if len(articles) == 0 {
+		return
+	}
+
+	indexes := make(chan int)
+
+	var wg sync.WaitGroup
+
+	for range max(1, min(s.config.Extractor.Workers, len(articles))) {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for i := range indexes {
+				article := articles[i]
+
+				result, err := s.extractor.extract(ctx, article.Link)
+				if err != nil {
+					log.Printf("failed to extract article %s: %v\n", article.Link, err)
+					continue
+				}
+
+				article.Content = result.content
+				article.WordCount = result.wordCount
+				article.ReadingTimeMinutes = readingTime(result.wordCount, s.config.Extractor.WordsPerMinute)
+				article.LeadImage = result.leadImage
+			}
+		}()
+	}
+
+	for i := range articles {
+		indexes <- i
+	}
+
+	close(indexes)
+	wg.Wait()
+}
+
+// isArticleUpdated returns true if the feed item has been updated
+// since the article was stored, either by its updated date or its content
+func isArticleUpdated(previous, article model.Article) bool {
+	previousHash := previous.ContentHash
+	if previousHash == "" {
+		// articles stored before the content hash was introduced
+		previousHash = contentHash(previous)
+	}
+
+	// articles stored before the normalisation was introduced have a hash of their raw title and description
+	if previousHash != article.ContentHash && normalizedContentHash(previous) != article.ContentHash {
+		return true
+	}
+
+	return previous.UpdatedDateTime != nil && article.UpdatedDateTime != nil &&
+		article.UpdatedDateTime.After(*previous.UpdatedDateTime)
+}
+
+// contentHash of the title and description of an article
+func contentHash(article model.Article) string {
+	return hash(article.Title + "\x00" + article.Descriptiopn)
+}
+
+// normalizedContentHash of the title and description of an article as they are normalised now
+func normalizedContentHash(article model.Article) string {
+	article.Title = normalizeTitle(article.Title)
+	article.Descriptiopn = cleanText(article.Descriptiopn)
+
+	return contentHash(article)
 }
 
 // getSources from a feedURL
-// it returns default sources if feedURL is not provided or not found
-func (s *service) getSources(feedURL string) []model.Source {
-	var sources = make([]model.Source, 0)
+// it returns all registered sources (but the manual ones) if feedURL is not provided
+// and an unregistered source (without id) if feedURL is not found
+func (s *service) getSources(ctx context.Context, feedURL string) ([]model.Source, error) {
+	if feedURL == "" {
+		sources, err := s.sourceRepository.FindAll(ctx)
+		if err != nil {
+			return nil, err
+		}
 
-	if _, ok := model.DefaultSources[feedURL]; !ok {
-		for _, source := range model.DefaultSources {
-			sources = append(sources, source)
+		return slices.DeleteFunc(sources, func(source model.Source) bool { return source.Manual }), nil
+	}
+
+	source, err := s.sourceRepository.FindByFeedURL(ctx, feedURL)
+	if err == nil {
+		return []model.Source{source}, nil
+	}
+
+	if !errors.Is(err, ErrNotFound) {
+		return nil, err
+	}
+
+	if !isFeedURL(feedURL) {
+		return nil, fmt.Errorf("%w: %s is not a valid http(s) url", ErrInvalidFeed, feedURL)
+	}
+
+	return []model.Source{{FeedURL: feedURL}}, nil
+}
+
+// isFeedURL returns true for absolute http(s) urls
+func isFeedURL(feedURL string) bool {
+	u, err := url.Parse(feedURL)
+
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// createAdHocSource registers a source for a feed loaded by url
+// provider and category are derived from the feed metadata when not provided
+func (s *service) createAdHocSource(ctx context.Context, source model.Source, feed *gofeed.Feed) (model.Source, error) {
+	source = newAdHocSource(source, feed)
+	source.ID = newID()
+
+	if err := s.sourceRepository.Create(ctx, source); err != nil {
+		// it could have been registered by a concurrent load
+		if errors.Is(err, ErrAlreadyExists) {
+			return s.sourceRepository.FindByFeedURL(ctx, source.FeedURL)
 		}
 
-		return sources
+		return model.Source{}, err
 	}
 
-	sources = append(sources, model.DefaultSources[feedURL])
+	return source, nil
+}
 
-	return sources
+// newAdHocSource derives the missing provider and category of a source from the feed metadata
+func newAdHocSource(source model.Source, feed *gofeed.Feed) model.Source {
+	if source.Category == "" {
+		source.Category = deriveCategory(source.FeedURL, feed)
+	}
+
+	if source.Provider == "" {
+		source.Provider = deriveProvider(source.FeedURL, feed)
+	}
+
+	source.AdHoc = true
+
+	return source
 }
 
-// parseFeed and returns the slice of articles
-func (s *service) parseFeed(feed *gofeed.Feed) ([]model.Article, error) {
+// parseFeed and returns the slice of normalised articles
+func (s *service) parseFeed(feed *gofeed.Feed, source model.Source, fetchedAt time.Time) ([]model.Article, error) {
 	if feed == nil || feed.Items == nil {
 		return nil, errors.New("no feed or articles found")
 	}
 
+	policy := source.GetLinkPolicy()
+
 	var articles = make(model.Articles, len(feed.Items))
 	for i, item := range feed.Items {
 		article := model.Article{
-			ID:                item.GUID,
+			GUID:              item.GUID,
 			Title:             item.Title,
 			Descriptiopn:      item.Description,
 			Link:              item.Link,
-			Source:            s.getSourceByLink(item.Link),
+			Source:            source.Reference(),
 			PublishedDateTime: item.PublishedParsed,
 			UpdatedDateTime:   item.UpdatedParsed,
+			Authors:           itemAuthors(item),
+			Tags:              itemTags(item),
+			Image:             itemImage(item),
+			Enclosures:        itemEnclosures(item),
+			FeedContent:       item.Content,
 		}
 
+		normalizeArticle(&article, feed, fetchedAt)
+
+		article.DescriptionHTML = sanitizeHTML(article.Descriptiopn)
+		article.DescriptionText = plainText(article.Descriptiopn)
+		article.FeedContentHTML = sanitizeHTML(article.FeedContent)
+		article.FeedContentText = plainText(article.FeedContent)
+
+		article.CanonicalLink = canonicalLink(article.Link, policy)
+		article.ContentHash = contentHash(article)
+		article.ID = articleID(item, source.IDStrategy, article.CanonicalLink, article.ContentHash)
+		article.Fingerprint, article.FingerprintBands = fingerprint(article)
+
 		articles[i] = article
 	}
 
 	return articles, nil
 }
 
-// getSourceByLink-  workaround to find the source based on the article link as bbc doesn't have
-// feedLink to easily being identified and the feed link provided is the same for News and Tech
-func (s *service) getSourceByLink(link string) model.Source {
-	// it means it's bbc source
-	if strings.Contains(link, model.SourceBBC) {
-		if strings.Contains(link, model.CategoryTechnology) {
-			return model.DefaultSources[fmt.Sprintf("%s/%s", model.SourceBBC, model.CategoryTechnology)]
+// deriveProvider uses the registrable domain of the feed website (or the feed itself)
+// e.g. https://news.sky.com -> sky, https://www.bbc.co.uk/news -> bbc
+func deriveProvider(feedURL string, feed *gofeed.Feed) string {
+	for _, link := range []string{feed.Link, feedURL} {
+		u, err := url.Parse(link)
+		if err != nil || u.Hostname() == "" {
+			continue
 		}
 
-		return model.DefaultSources[fmt.Sprintf("%s/%s", model.SourceBBC, model.CategoryUK)]
+		domain, err := publicsuffix.EffectiveTLDPlusOne(u.Hostname())
+		if err != nil {
+			continue
+		}
+
+		return strings.SplitN(domain, ".", 2)[0]
+	}
+
+	return defaultProvider
+}
+
+// deriveCategory uses the first feed category, otherwise the last meaningful
+// segment of the feed url e.g. https://feeds.bbci.co.uk/news/world/rss.xml -> world
+func deriveCategory(feedURL string, feed *gofeed.Feed) string {
+	if len(feed.Categories) > 0 && strings.TrimSpace(feed.Categories[0]) != "" {
+		return strings.ToLower(strings.TrimSpace(feed.Categories[0]))
 	}
 
-	// doing the same for sky to be consitent, but didn't really need to
-	if strings.Contains(link, model.SourceSky) {
-		if strings.Contains(link, model.CategoryTechnology) {
-			return model.DefaultSources[fmt.Sprintf("%s/%s", model.SourceSky, model.CategoryTechnology)]
+	u, err := url.Parse(feedURL)
+	if err != nil {
+		return defaultCategory
+	}
+
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	for i := len(segments) - 1; i >= 0; i-- {
+		segment := strings.ToLower(strings.TrimSuffix(segments[i], path.Ext(segments[i])))
+
+		switch segment {
+		case "", "rss", "feed", "feeds", "atom", "index":
+			continue
 		}
 
-		return model.DefaultSources[fmt.Sprintf("%s/%s", model.SourceSky, model.CategoryUK)]
+		return segment
+	}
+
+	return defaultCategory
+}
+
+// newID generates a new unique id for sources, rules, jobs and snapshots
+func newID() string {
+	return primitive.NewObjectID().Hex()
+}
+
+// seedSources registers the default sources when the registry is empty
+func seedSources(ctx context.Context, sourceRepository SourceRepository) error {
+	sources := make([]model.Source, len(model.DefaultSources))
+	for i, source := range model.DefaultSources {
+		source.ID = newID()
+		sources[i] = source
 	}
 
-	return model.Source{}
+	return sourceRepository.Seed(ctx, sources)
 }
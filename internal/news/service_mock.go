@@ -7,6 +7,7 @@ package news
 import (
 	context "context"
 	model "go-news-feed/pkg/model"
+	io "io"
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
@@ -35,6 +36,108 @@ func (m *MockService) EXPECT() *MockServiceMockRecorder {
 	return m.recorder
 }
 
+// CreateLoadJob mocks base method.
+func (m *MockService) CreateLoadJob(ctx context.Context, lr model.LoadRequest) (model.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateLoadJob", ctx, lr)
+	ret0, _ := ret[0].(model.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateLoadJob indicates an expected call of CreateLoadJob.
+func (mr *MockServiceMockRecorder) CreateLoadJob(ctx, lr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLoadJob", reflect.TypeOf((*MockService)(nil).CreateLoadJob), ctx, lr)
+}
+
+// CreateRule mocks base method.
+func (m *MockService) CreateRule(ctx context.Context, rule model.Rule) (model.Rule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRule", ctx, rule)
+	ret0, _ := ret[0].(model.Rule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateRule indicates an expected call of CreateRule.
+func (mr *MockServiceMockRecorder) CreateRule(ctx, rule interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRule", reflect.TypeOf((*MockService)(nil).CreateRule), ctx, rule)
+}
+
+// CreateSource mocks base method.
+func (m *MockService) CreateSource(ctx context.Context, source model.Source) (model.Source, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSource", ctx, source)
+	ret0, _ := ret[0].(model.Source)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateSource indicates an expected call of CreateSource.
+func (mr *MockServiceMockRecorder) CreateSource(ctx, source interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSource", reflect.TypeOf((*MockService)(nil).CreateSource), ctx, source)
+}
+
+// DeleteRule mocks base method.
+func (m *MockService) DeleteRule(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteRule", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteRule indicates an expected call of DeleteRule.
+func (mr *MockServiceMockRecorder) DeleteRule(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRule", reflect.TypeOf((*MockService)(nil).DeleteRule), ctx, id)
+}
+
+// DeleteSource mocks base method.
+func (m *MockService) DeleteSource(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSource", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteSource indicates an expected call of DeleteSource.
+func (mr *MockServiceMockRecorder) DeleteSource(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSource", reflect.TypeOf((*MockService)(nil).DeleteSource), ctx, id)
+}
+
+// DiscardQuarantinedItem mocks base method.
+func (m *MockService) DiscardQuarantinedItem(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DiscardQuarantinedItem", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DiscardQuarantinedItem indicates an expected call of DiscardQuarantinedItem.
+func (mr *MockServiceMockRecorder) DiscardQuarantinedItem(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DiscardQuarantinedItem", reflect.TypeOf((*MockService)(nil).DiscardQuarantinedItem), ctx, id)
+}
+
+// DryRunRule mocks base method.
+func (m *MockService) DryRunRule(ctx context.Context, rule model.Rule) (model.DryRunResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DryRunRule", ctx, rule)
+	ret0, _ := ret[0].(model.DryRunResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DryRunRule indicates an expected call of DryRunRule.
+func (mr *MockServiceMockRecorder) DryRunRule(ctx, rule interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DryRunRule", reflect.TypeOf((*MockService)(nil).DryRunRule), ctx, rule)
+}
+
 // Find mocks base method.
 func (m *MockService) Find(ctx context.Context, sr model.FindRequest) (model.FindResponse, error) {
 	m.ctrl.T.Helper()
@@ -50,17 +153,317 @@ func (mr *MockServiceMockRecorder) Find(ctx, sr interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockService)(nil).Find), ctx, sr)
 }
 
+// FindArticleByID mocks base method.
+func (m *MockService) FindArticleByID(ctx context.Context, id string) (model.Article, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindArticleByID", ctx, id)
+	ret0, _ := ret[0].(model.Article)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindArticleByID indicates an expected call of FindArticleByID.
+func (mr *MockServiceMockRecorder) FindArticleByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindArticleByID", reflect.TypeOf((*MockService)(nil).FindArticleByID), ctx, id)
+}
+
+// FindLoadJob mocks base method.
+func (m *MockService) FindLoadJob(ctx context.Context, id string) (model.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindLoadJob", ctx, id)
+	ret0, _ := ret[0].(model.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindLoadJob indicates an expected call of FindLoadJob.
+func (mr *MockServiceMockRecorder) FindLoadJob(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindLoadJob", reflect.TypeOf((*MockService)(nil).FindLoadJob), ctx, id)
+}
+
+// FindLoadJobs mocks base method.
+func (m *MockService) FindLoadJobs(ctx context.Context, jr model.JobRequest) (model.JobResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindLoadJobs", ctx, jr)
+	ret0, _ := ret[0].(model.JobResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindLoadJobs indicates an expected call of FindLoadJobs.
+func (mr *MockServiceMockRecorder) FindLoadJobs(ctx, jr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindLoadJobs", reflect.TypeOf((*MockService)(nil).FindLoadJobs), ctx, jr)
+}
+
+// FindQuarantine mocks base method.
+func (m *MockService) FindQuarantine(ctx context.Context, qr model.QuarantineRequest) (model.QuarantineResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindQuarantine", ctx, qr)
+	ret0, _ := ret[0].(model.QuarantineResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindQuarantine indicates an expected call of FindQuarantine.
+func (mr *MockServiceMockRecorder) FindQuarantine(ctx, qr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindQuarantine", reflect.TypeOf((*MockService)(nil).FindQuarantine), ctx, qr)
+}
+
+// FindQuarantinedItem mocks base method.
+func (m *MockService) FindQuarantinedItem(ctx context.Context, id string) (model.QuarantinedItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindQuarantinedItem", ctx, id)
+	ret0, _ := ret[0].(model.QuarantinedItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindQuarantinedItem indicates an expected call of FindQuarantinedItem.
+func (mr *MockServiceMockRecorder) FindQuarantinedItem(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindQuarantinedItem", reflect.TypeOf((*MockService)(nil).FindQuarantinedItem), ctx, id)
+}
+
+// FindRevisions mocks base method.
+func (m *MockService) FindRevisions(ctx context.Context, articleID string) ([]model.RevisionDiff, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindRevisions", ctx, articleID)
+	ret0, _ := ret[0].([]model.RevisionDiff)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindRevisions indicates an expected call of FindRevisions.
+func (mr *MockServiceMockRecorder) FindRevisions(ctx, articleID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindRevisions", reflect.TypeOf((*MockService)(nil).FindRevisions), ctx, articleID)
+}
+
+// FindRuleByID mocks base method.
+func (m *MockService) FindRuleByID(ctx context.Context, id string) (model.Rule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindRuleByID", ctx, id)
+	ret0, _ := ret[0].(model.Rule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindRuleByID indicates an expected call of FindRuleByID.
+func (mr *MockServiceMockRecorder) FindRuleByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindRuleByID", reflect.TypeOf((*MockService)(nil).FindRuleByID), ctx, id)
+}
+
+// FindRules mocks base method.
+func (m *MockService) FindRules(ctx context.Context, sourceID string) ([]model.Rule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindRules", ctx, sourceID)
+	ret0, _ := ret[0].([]model.Rule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindRules indicates an expected call of FindRules.
+func (mr *MockServiceMockRecorder) FindRules(ctx, sourceID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindRules", reflect.TypeOf((*MockService)(nil).FindRules), ctx, sourceID)
+}
+
+// FindSnapshot mocks base method.
+func (m *MockService) FindSnapshot(ctx context.Context, id string) (model.Snapshot, []byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindSnapshot", ctx, id)
+	ret0, _ := ret[0].(model.Snapshot)
+	ret1, _ := ret[1].([]byte)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// FindSnapshot indicates an expected call of FindSnapshot.
+func (mr *MockServiceMockRecorder) FindSnapshot(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindSnapshot", reflect.TypeOf((*MockService)(nil).FindSnapshot), ctx, id)
+}
+
+// FindSnapshots mocks base method.
+func (m *MockService) FindSnapshots(ctx context.Context, sr model.SnapshotRequest) (model.SnapshotResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindSnapshots", ctx, sr)
+	ret0, _ := ret[0].(model.SnapshotResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindSnapshots indicates an expected call of FindSnapshots.
+func (mr *MockServiceMockRecorder) FindSnapshots(ctx, sr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindSnapshots", reflect.TypeOf((*MockService)(nil).FindSnapshots), ctx, sr)
+}
+
+// FindSourceByID mocks base method.
+func (m *MockService) FindSourceByID(ctx context.Context, id string) (model.Source, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindSourceByID", ctx, id)
+	ret0, _ := ret[0].(model.Source)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindSourceByID indicates an expected call of FindSourceByID.
+func (mr *MockServiceMockRecorder) FindSourceByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindSourceByID", reflect.TypeOf((*MockService)(nil).FindSourceByID), ctx, id)
+}
+
+// FindSourceStatus mocks base method.
+func (m *MockService) FindSourceStatus(ctx context.Context, id string) (model.SourceStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindSourceStatus", ctx, id)
+	ret0, _ := ret[0].(model.SourceStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindSourceStatus indicates an expected call of FindSourceStatus.
+func (mr *MockServiceMockRecorder) FindSourceStatus(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindSourceStatus", reflect.TypeOf((*MockService)(nil).FindSourceStatus), ctx, id)
+}
+
+// FindSources mocks base method.
+func (m *MockService) FindSources(ctx context.Context) ([]model.Source, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindSources", ctx)
+	ret0, _ := ret[0].([]model.Source)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindSources indicates an expected call of FindSources.
+func (mr *MockServiceMockRecorder) FindSources(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindSources", reflect.TypeOf((*MockService)(nil).FindSources), ctx)
+}
+
+// FindStory mocks base method.
+func (m *MockService) FindStory(ctx context.Context, id string) (model.Story, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindStory", ctx, id)
+	ret0, _ := ret[0].(model.Story)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindStory indicates an expected call of FindStory.
+func (mr *MockServiceMockRecorder) FindStory(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindStory", reflect.TypeOf((*MockService)(nil).FindStory), ctx, id)
+}
+
 // Load mocks base method.
-func (m *MockService) Load(ctx context.Context, feedURL string) ([]model.Article, error) {
+func (m *MockService) Load(ctx context.Context, lr model.LoadRequest) (model.LoadReport, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Load", ctx, feedURL)
-	ret0, _ := ret[0].([]model.Article)
+	ret := m.ctrl.Call(m, "Load", ctx, lr)
+	ret0, _ := ret[0].(model.LoadReport)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Load indicates an expected call of Load.
-func (mr *MockServiceMockRecorder) Load(ctx, feedURL interface{}) *gomock.Call {
+func (mr *MockServiceMockRecorder) Load(ctx, lr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Load", reflect.TypeOf((*MockService)(nil).Load), ctx, lr)
+}
+
+// LoadDocument mocks base method.
+func (m *MockService) LoadDocument(ctx context.Context, ur model.UploadRequest, document io.Reader) (model.LoadReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadDocument", ctx, ur, document)
+	ret0, _ := ret[0].(model.LoadReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadDocument indicates an expected call of LoadDocument.
+func (mr *MockServiceMockRecorder) LoadDocument(ctx, ur, document interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadDocument", reflect.TypeOf((*MockService)(nil).LoadDocument), ctx, ur, document)
+}
+
+// ReplaySnapshot mocks base method.
+func (m *MockService) ReplaySnapshot(ctx context.Context, id string, dryRun bool) (model.LoadReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaySnapshot", ctx, id, dryRun)
+	ret0, _ := ret[0].(model.LoadReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReplaySnapshot indicates an expected call of ReplaySnapshot.
+func (mr *MockServiceMockRecorder) ReplaySnapshot(ctx, id, dryRun interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaySnapshot", reflect.TypeOf((*MockService)(nil).ReplaySnapshot), ctx, id, dryRun)
+}
+
+// ReprocessQuarantinedItem mocks base method.
+func (m *MockService) ReprocessQuarantinedItem(ctx context.Context, id string) (model.Article, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReprocessQuarantinedItem", ctx, id)
+	ret0, _ := ret[0].(model.Article)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReprocessQuarantinedItem indicates an expected call of ReprocessQuarantinedItem.
+func (mr *MockServiceMockRecorder) ReprocessQuarantinedItem(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReprocessQuarantinedItem", reflect.TypeOf((*MockService)(nil).ReprocessQuarantinedItem), ctx, id)
+}
+
+// RunLoadJob mocks base method.
+func (m *MockService) RunLoadJob(ctx context.Context, job model.Job) model.Job {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RunLoadJob", ctx, job)
+	ret0, _ := ret[0].(model.Job)
+	return ret0
+}
+
+// RunLoadJob indicates an expected call of RunLoadJob.
+func (mr *MockServiceMockRecorder) RunLoadJob(ctx, job interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunLoadJob", reflect.TypeOf((*MockService)(nil).RunLoadJob), ctx, job)
+}
+
+// UpdateRule mocks base method.
+func (m *MockService) UpdateRule(ctx context.Context, rule model.Rule) (model.Rule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateRule", ctx, rule)
+	ret0, _ := ret[0].(model.Rule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateRule indicates an expected call of UpdateRule.
+func (mr *MockServiceMockRecorder) UpdateRule(ctx, rule interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRule", reflect.TypeOf((*MockService)(nil).UpdateRule), ctx, rule)
+}
+
+// UpdateSource mocks base method.
+func (m *MockService) UpdateSource(ctx context.Context, source model.Source) (model.Source, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSource", ctx, source)
+	ret0, _ := ret[0].(model.Source)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateSource indicates an expected call of UpdateSource.
+func (mr *MockServiceMockRecorder) UpdateSource(ctx, source interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Load", reflect.TypeOf((*MockService)(nil).Load), ctx, feedURL)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSource", reflect.TypeOf((*MockService)(nil).UpdateSource), ctx, source)
 }
@@ -0,0 +1,88 @@
+package news
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"log"
+
+	"go-news-feed/pkg/model"
+)
+
+// saveSnapshot archives the body of a fetched feed, it expires after the retention
+// a snapshot that can't be archived doesn't fail the load
+func (s *service) saveSnapshot(ctx context.Context, source model.Source, fr fetchResult, fetchErr error) {
+	if !s.config.Snapshots.Enabled {
+		return
+	}
+
+	body, err := compress(fr.body)
+	if err != nil {
+		log.Printf("failed to compress snapshot of %s: %v\n", source.FeedURL, err)
+		return
+	}
+
+	snapshot := model.Snapshot{
+		ID:        newID(),
+		Source:    source.Reference(),
+		Header:    fr.header,
+		FetchedAt: fr.fetchedAt,
+		Size:      len(fr.body),
+		Body:      body,
+	}
+
+	if fetchErr != nil {
+		snapshot.Error = fetchErr.Error()
+	}
+
+	if err := s.snapshotRepository.Create(ctx, snapshot); err != nil {
+		log.Printf("failed to save snapshot of %s: %v\n", source.FeedURL, err)
+	}
+}
+
+// snapshotSource returns the source of a snapshot as it is now registered
+// a feed archived before it was registered (e.g. an ad-hoc feed failing to parse) is looked up by url
+func (s *service) snapshotSource(ctx context.Context, snapshot model.Snapshot) (model.Source, error) {
+	if snapshot.Source.ID != "" {
+		source, err := s.sourceRepository.FindByID(ctx, snapshot.Source.ID)
+		if !errors.Is(err, ErrNotFound) {
+			return source, err
+		}
+	}
+
+	source, err := s.sourceRepository.FindByFeedURL(ctx, snapshot.Source.FeedURL)
+	if errors.Is(err, ErrNotFound) {
+		return model.Source{FeedURL: snapshot.Source.FeedURL}, nil
+	}
+
+	return source, err
+}
+
+// compress returns the body gzip compressed
+func compress(body []byte) ([]byte, error) {
+	var buf bytes.Buffer
+
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write(body); err != nil {
+		return nil, err
+	}
+
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// decompress returns the gzip compressed body as it was
+func decompress(body []byte) ([]byte, error) {
+	r, err := gzip.NewReader(bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	return io.ReadAll(r)
+}
@@ -0,0 +1,126 @@
+package news
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"go-news-feed/pkg/model"
+)
+
+// SnapshotRepository - interface
+//
+//go:generate mockgen -source=snapshot_repository.go -destination=snapshot_repository_mock.go --package=news
+type SnapshotRepository interface {
+	Create(ctx context.Context, snapshot model.Snapshot) error
+	FindByID(ctx context.Context, id string) (model.Snapshot, error)
+	Find(ctx context.Context, sr model.SnapshotRequest) (model.SnapshotResponse, error)
+}
+
+type snapshotRepository struct {
+	collection *mongo.Collection
+}
+
+// newSnapshotRepository - constructor
+// snapshots are dropped by mongo once they are older than the retention
+func newSnapshotRepository(ctx context.Context, db *mongo.Database, config MongoConfig, retention time.Duration) (SnapshotRepository, error) {
+	collection := db.Collection(config.SnapshotCollection)
+
+	_, err := collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "source._id", Value: 1}, {Key: "fetchedAt", Value: -1}}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ensureSnapshotTTL(ctx, db, collection, retention); err != nil {
+		return nil, err
+	}
+
+	return &snapshotRepository{collection: collection}, nil
+}
+
+// snapshotTTLIndex is the name of the index expiring the snapshots
+const snapshotTTLIndex = "fetchedAt_ttl"
+
+// ensureSnapshotTTL creates the index expiring the snapshots after the retention
+// or updates its expiry when the retention has changed since it was created
+func ensureSnapshotTTL(ctx context.Context, db *mongo.Database, collection *mongo.Collection, retention time.Duration) error {
+	seconds := int32(retention.Seconds())
+
+	_, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "fetchedAt", Value: 1}},
+		Options: options.Index().SetName(snapshotTTLIndex).SetExpireAfterSeconds(seconds),
+	})
+
+	var cmdErr mongo.CommandError
+	if !errors.As(err, &cmdErr) || cmdErr.Name != "IndexOptionsConflict" {
+		return err
+	}
+
+	return db.RunCommand(ctx, bson.D{
+		{Key: "collMod", Value: collection.Name()},
+		{Key: "index", Value: bson.D{{Key: "name", Value: snapshotTTLIndex}, {Key: "expireAfterSeconds", Value: seconds}}},
+	}).Err()
+}
+
+func (r snapshotRepository) Create(ctx context.Context, snapshot model.Snapshot) error {
+	_, err := r.collection.InsertOne(ctx, &snapshot)
+
+	return err
+}
+
+// FindByID returns the snapshot along with its body
+func (r snapshotRepository) FindByID(ctx context.Context, id string) (model.Snapshot, error) {
+	var snapshot model.Snapshot
+
+	if err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&snapshot); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return model.Snapshot{}, ErrNotFound
+		}
+
+		return model.Snapshot{}, err
+	}
+
+	return snapshot, nil
+}
+
+// Find returns the snapshots (of the source, if any) without their body, latest first
+func (r snapshotRepository) Find(ctx context.Context, sr model.SnapshotRequest) (model.SnapshotResponse, error) {
+	filter := bson.M{}
+	if sr.SourceID != "" {
+		filter["source._id"] = sr.SourceID
+	}
+
+	limit := sr.Limit
+	if limit == 0 || limit > maxLimit {
+		limit = maxLimit
+	}
+
+	total, err := r.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return model.SnapshotResponse{}, err
+	}
+
+	opts := options.Find().
+		SetProjection(bson.M{"body": 0}).
+		SetSort(bson.D{{Key: "fetchedAt", Value: -1}, {Key: "_id", Value: 1}}).
+		SetSkip(int64(sr.Page * limit)).
+		SetLimit(int64(limit))
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return model.SnapshotResponse{}, err
+	}
+
+	snapshots := make([]model.Snapshot, 0)
+	if err := cursor.All(ctx, &snapshots); err != nil {
+		return model.SnapshotResponse{}, err
+	}
+
+	return model.SnapshotResponse{Criteria: sr, Snapshots: snapshots, Total: int(total)}, nil
+}
@@ -0,0 +1,211 @@
+package news
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"go-news-feed/pkg/model"
+)
+
+// SourceRepository - interface
+//
+//go:generate mockgen -source=source_repository.go -destination=source_repository_mock.go --package=news
+type SourceRepository interface {
+	FindByID(ctx context.Context, id string) (model.Source, error)
+	FindByFeedURL(ctx context.Context, feedURL string) (model.Source, error)
+	FindAll(ctx context.Context) ([]model.Source, error)
+	Create(ctx context.Context, source model.Source) error
+	Update(ctx context.Context, source model.Source) error
+	Delete(ctx context.Context, id string) error
+	Seed(ctx context.Context, sources []model.Source) error
+	UpdateSchedule(ctx context.Context, id string, lastRunAt, nextRunAt time.Time, lastError string) error
+	UpdateFetchState(ctx context.Context, id, etag, lastModified string) error
+}
+
+type sourceRepository struct {
+	collection *mongo.Collection
+}
+
+// newSourceRepository - constructor
+// it also makes sure the same feed url can't be registered twice
+func newSourceRepository(ctx context.Context, db *mongo.Database, config MongoConfig) (SourceRepository, error) {
+	collection := db.Collection(config.SourceCollection)
+
+	_, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "feedUrl", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &sourceRepository{collection: collection}, nil
+}
+
+func (r sourceRepository) FindByID(ctx context.Context, id string) (model.Source, error) {
+	return r.findOne(ctx, bson.M{"_id": id})
+}
+
+func (r sourceRepository) FindByFeedURL(ctx context.Context, feedURL string) (model.Source, error) {
+	return r.findOne(ctx, bson.M{"feedUrl": feedURL})
+}
+
+func (r sourceRepository) FindAll(ctx context.Context) ([]model.Source, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+
+	sources := make([]model.Source, 0)
+	if err := cursor.All(ctx, &sources); err != nil {
+		return nil, err
+	}
+
+	return sources, nil
+}
+
+func (r sourceRepository) Create(ctx context.Context, source model.Source) error {
+	_, err := r.collection.InsertOne(ctx, &source)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return ErrAlreadyExists
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// editableSourceFields are the fields of a source set by its owner, the status is left to the server
+var editableSourceFields = []string{
+	"category", "feedUrl", "provider", "adHoc", "manual", "pollIntervalSeconds",
+	"idStrategy", "linkPolicy", "pipeline", "extract", "http",
+}
+
+// Update sets the editable fields of the source, those left empty are removed
+// the status isn't replaced as the scheduler may be recording a run meanwhile
+func (r sourceRepository) Update(ctx context.Context, source model.Source) error {
+	source.Status = nil
+
+	document, err := bson.Marshal(source)
+	if err != nil {
+		return err
+	}
+
+	var fields bson.M
+	if err := bson.Unmarshal(document, &fields); err != nil {
+		return err
+	}
+
+	delete(fields, "_id")
+
+	unset := bson.M{}
+	for _, field := range editableSourceFields {
+		if _, ok := fields[field]; !ok {
+			unset[field] = ""
+		}
+	}
+
+	update := bson.M{"$set": fields}
+	if len(unset) > 0 {
+		update["$unset"] = unset
+	}
+
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": source.ID}, update)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return ErrAlreadyExists
+		}
+
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+func (r sourceRepository) Delete(ctx context.Context, id string) error {
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+
+	if result.DeletedCount == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// Seed inserts the sources provided only when the registry is empty
+// the feeds already registered are skipped
+func (r sourceRepository) Seed(ctx context.Context, sources []model.Source) error {
+	count, err := r.collection.CountDocuments(ctx, bson.M{})
+	if err != nil {
+		return err
+	}
+
+	if count > 0 {
+		return nil
+	}
+
+	documents := make([]any, len(sources))
+	for i := range sources {
+		documents[i] = sources[i]
+	}
+
+	_, err = r.collection.InsertMany(ctx, documents, options.InsertMany().SetOrdered(false))
+
+	// another instance starting at the same time may have seeded the same feeds
+	_, err = duplicateKeyErrors(err)
+
+	return err
+}
+
+// UpdateSchedule records the last and next scheduled runs of a source
+func (r sourceRepository) UpdateSchedule(ctx context.Context, id string, lastRunAt, nextRunAt time.Time, lastError string) error {
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{
+		"$set": bson.M{
+			"status.lastRunAt": lastRunAt,
+			"status.nextRunAt": nextRunAt,
+			"status.lastError": lastError,
+		},
+	})
+
+	return err
+}
+
+// UpdateFetchState records the validators used for conditional requests
+func (r sourceRepository) UpdateFetchState(ctx context.Context, id, etag, lastModified string) error {
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{
+		"$set": bson.M{
+			"status.etag":         etag,
+			"status.lastModified": lastModified,
+		},
+	})
+
+	return err
+}
+
+func (r sourceRepository) findOne(ctx context.Context, filter bson.M) (model.Source, error) {
+	var source model.Source
+
+	if err := r.collection.FindOne(ctx, filter).Decode(&source); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return model.Source{}, ErrNotFound
+		}
+
+		return model.Source{}, err
+	}
+
+	return source, nil
+}
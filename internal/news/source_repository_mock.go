@@ -0,0 +1,166 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: source_repository.go
+
+// Package news is a generated GoMock package.
+package news
+
+import (
+	context "context"
+	model "go-news-feed/pkg/model"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockSourceRepository is a mock of SourceRepository interface.
+type MockSourceRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockSourceRepositoryMockRecorder
+}
+
+// MockSourceRepositoryMockRecorder is the mock recorder for MockSourceRepository.
+type MockSourceRepositoryMockRecorder struct {
+	mock *MockSourceRepository
+}
+
+// NewMockSourceRepository creates a new mock instance.
+func NewMockSourceRepository(ctrl *gomock.Controller) *MockSourceRepository {
+	mock := &MockSourceRepository{ctrl: ctrl}
+	mock.recorder = &MockSourceRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSourceRepository) EXPECT() *MockSourceRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockSourceRepository) Create(ctx context.Context, source model.Source) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, source)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockSourceRepositoryMockRecorder) Create(ctx, source interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockSourceRepository)(nil).Create), ctx, source)
+}
+
+// Delete mocks base method.
+func (m *MockSourceRepository) Delete(ctx context.Context, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockSourceRepositoryMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockSourceRepository)(nil).Delete), ctx, id)
+}
+
+// FindAll mocks base method.
+func (m *MockSourceRepository) FindAll(ctx context.Context) ([]model.Source, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAll", ctx)
+	ret0, _ := ret[0].([]model.Source)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindAll indicates an expected call of FindAll.
+func (mr *MockSourceRepositoryMockRecorder) FindAll(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockSourceRepository)(nil).FindAll), ctx)
+}
+
+// FindByFeedURL mocks base method.
+func (m *MockSourceRepository) FindByFeedURL(ctx context.Context, feedURL string) (model.Source, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByFeedURL", ctx, feedURL)
+	ret0, _ := ret[0].(model.Source)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByFeedURL indicates an expected call of FindByFeedURL.
+func (mr *MockSourceRepositoryMockRecorder) FindByFeedURL(ctx, feedURL interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByFeedURL", reflect.TypeOf((*MockSourceRepository)(nil).FindByFeedURL), ctx, feedURL)
+}
+
+// FindByID mocks base method.
+func (m *MockSourceRepository) FindByID(ctx context.Context, id string) (model.Source, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByID", ctx, id)
+	ret0, _ := ret[0].(model.Source)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByID indicates an expected call of FindByID.
+func (mr *MockSourceRepositoryMockRecorder) FindByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockSourceRepository)(nil).FindByID), ctx, id)
+}
+
+// Seed mocks base method.
+func (m *MockSourceRepository) Seed(ctx context.Context, sources []model.Source) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Seed", ctx, sources)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Seed indicates an expected call of Seed.
+func (mr *MockSourceRepositoryMockRecorder) Seed(ctx, sources interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Seed", reflect.TypeOf((*MockSourceRepository)(nil).Seed), ctx, sources)
+}
+
+// Update mocks base method.
+func (m *MockSourceRepository) Update(ctx context.Context, source model.Source) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, source)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockSourceRepositoryMockRecorder) Update(ctx, source interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockSourceRepository)(nil).Update), ctx, source)
+}
+
+// UpdateFetchState mocks base method.
+func (m *MockSourceRepository) UpdateFetchState(ctx context.Context, id, etag, lastModified string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateFetchState", ctx, id, etag, lastModified)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateFetchState indicates an expected call of UpdateFetchState.
+func (mr *MockSourceRepositoryMockRecorder) UpdateFetchState(ctx, id, etag, lastModified interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateFetchState", reflect.TypeOf((*MockSourceRepository)(nil).UpdateFetchState), ctx, id, etag, lastModified)
+}
+
+// UpdateSchedule mocks base method.
+func (m *MockSourceRepository) UpdateSchedule(ctx context.Context, id string, lastRunAt, nextRunAt time.Time, lastError string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSchedule", ctx, id, lastRunAt, nextRunAt, lastError)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateSchedule indicates an expected call of UpdateSchedule.
+func (mr *MockSourceRepositoryMockRecorder) UpdateSchedule(ctx, id, lastRunAt, nextRunAt, lastError interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSchedule", reflect.TypeOf((*MockSourceRepository)(nil).UpdateSchedule), ctx, id, lastRunAt, nextRunAt, lastError)
+}
@@ -0,0 +1,232 @@
+package news
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"math/bits"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"go-news-feed/pkg/model"
+)
+
+// fingerprintBands is the number of 16 bits chunks of a fingerprint
+// two fingerprints up to 3 bits apart always share at least one band
+const fingerprintBands = 4
+
+// stopWords say nothing about the story so they are left out of fingerprints
+var stopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true, "be": true, "by": true,
+	"for": true, "from": true, "has": true, "have": true, "he": true, "in": true, "is": true, "it": true,
+	"its": true, "of": true, "on": true, "or": true, "she": true, "that": true, "the": true, "their": true,
+	"they": true, "this": true, "to": true, "was": true, "were": true, "will": true, "with": true,
+}
+
+// fingerprint returns the SimHash of the title and description of an article and its bands
+// near duplicate texts have fingerprints differing by a few bits only
+// articles without any meaningful word don't have a fingerprint
+func fingerprint(article model.Article) (string, []string) {
+	words := strings.FieldsFunc(strings.ToLower(article.Title+" "+article.Descriptiopn), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	var weights [64]int
+
+	features := 0
+
+	for _, word := range words {
+		if stopWords[word] {
+			continue
+		}
+
+		h := fnv.New64a()
+		_, _ = h.Write([]byte(word))
+		sum := h.Sum64()
+
+		for i := range weights {
+			if sum&(1<<i) != 0 {
+				weights[i]++
+			} else {
+				weights[i]--
+			}
+		}
+
+		features++
+	}
+
+	if features == 0 {
+		return "", nil
+	}
+
+	var fp uint64
+
+	for i, weight := range weights {
+		if weight > 0 {
+			fp |= 1 << i
+		}
+	}
+
+	bands := make([]string, fingerprintBands)
+	for i := range bands {
+		bands[i] = fmt.Sprintf("%d:%04x", i, (fp>>(16*i))&0xffff)
+	}
+
+	return fmt.Sprintf("%016x", fp), bands
+}
+
+// distance returns the number of different bits of two fingerprints
+// or -1 if any of them is missing or invalid
+func distance(a, b string) int {
+	x, err := strconv.ParseUint(a, 16, 64)
+	if err != nil {
+		return -1
+	}
+
+	y, err := strconv.ParseUint(b, 16, 64)
+	if err != nil {
+		return -1
+	}
+
+	return bits.OnesCount64(x ^ y)
+}
+
+// assignStories sets the story of the new articles
+// an article joins the story of its closest near duplicate published within the window
+// either stored or earlier in the batch, otherwise it starts a story of its own
+func (s *service) assignStories(ctx context.Context, articles []model.Article) error {
+	if len(articles) == 0 {
+		return nil
+	}
+
+	candidates, err := s.findCandidates(ctx, articles)
+	if err != nil {
+		return err
+	}
+
+	for i := range articles {
+		article := &articles[i]
+		article.StoryID = article.ID
+
+		if match, ok := s.closestStory(*article, candidates); ok {
+			article.StoryID = match
+		}
+
+		if article.Fingerprint != "" {
+			candidates = append(candidates, *article)
+		}
+	}
+
+	return nil
+}
+
+// findCandidates returns the stored near duplicates of the articles
+// they are looked up for every group of articles published within a window of each other
+// around the group's own dates, so the candidates of a batch spanning days (e.g. a backfill)
+// aren't all taken up by its latest articles
+func (s *service) findCandidates(ctx context.Context, articles []model.Article) ([]model.Article, error) {
+	type dated struct {
+		published time.Time
+		bands     []string
+	}
+
+	sorted := make([]dated, len(articles))
+	for i, article := range articles {
+		sorted[i] = dated{published: publishedAt(article), bands: article.FingerprintBands}
+	}
+
+	slices.SortFunc(sorted, func(a, b dated) int { return a.published.Compare(b.published) })
+
+	var (
+		candidates = make([]model.Article, 0)
+		found      = make(map[string]bool)
+	)
+
+	for start := 0; start < len(sorted); {
+		var (
+			from  = sorted[start].published
+			end   = start
+			bands = make([]string, 0)
+			seen  = make(map[string]bool)
+		)
+
+		for ; end < len(sorted) && sorted[end].published.Sub(from) <= s.config.Cluster.Window; end++ {
+			for _, band := range sorted[end].bands {
+				if !seen[band] {
+					seen[band] = true
+					bands = append(bands, band)
+				}
+			}
+		}
+
+		to := sorted[end-1].published
+		start = end
+
+		if len(bands) == 0 {
+			continue
+		}
+
+		similar, err := s.repository.FindSimilar(ctx, bands, from.Add(-s.config.Cluster.Window), to.Add(s.config.Cluster.Window))
+		if err != nil {
+			return nil, err
+		}
+
+		// the windows of consecutive groups overlap
+		for _, candidate := range similar {
+			if !found[candidate.ID] {
+				found[candidate.ID] = true
+				candidates = append(candidates, candidate)
+			}
+		}
+	}
+
+	return candidates, nil
+}
+
+// closestStory returns the story of the closest candidate to the article
+func (s *service) closestStory(article model.Article, candidates []model.Article) (string, bool) {
+	var (
+		story   string
+		closest = -1
+	)
+
+	published := publishedAt(article)
+
+	for _, candidate := range candidates {
+		if candidate.ID == article.ID {
+			continue
+		}
+
+		d := distance(article.Fingerprint, candidate.Fingerprint)
+		if d < 0 || d > s.config.Cluster.MaxDistance || (closest >= 0 && d >= closest) {
+			continue
+		}
+
+		if gap := published.Sub(publishedAt(candidate)).Abs(); gap > s.config.Cluster.Window {
+			continue
+		}
+
+		closest = d
+		story = candidate.StoryID
+
+		// articles stored before stories were introduced are their own story
+		if story == "" {
+			story = candidate.ID
+		}
+	}
+
+	return story, closest >= 0
+}
+
+// publishedAt returns the published time of an article
+// articles without it are considered just published
+func publishedAt(article model.Article) time.Time {
+	if article.PublishedDateTime == nil {
+		return time.Now().UTC()
+	}
+
+	return *article.PublishedDateTime
+}
@@ -0,0 +1,60 @@
+package news
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go-news-feed/pkg/model"
+)
+
+func TestFingerprint(t *testing.T) {
+	testCases := []struct {
+		name     string
+		given    [2]model.Article
+		expected func(t *testing.T, d int)
+	}{
+		{
+			name: "SameStoryDifferentStopWords",
+			given: [2]model.Article{
+				{Title: "Apple fined £1.5bn by EU over music streaming"},
+				{Title: "Apple fined £1.5bn by the EU over music streaming"},
+			},
+			expected: func(t *testing.T, d int) { assert.Equal(t, 0, d) },
+		},
+		{
+			name: "SameStoryDifferentCase",
+			given: [2]model.Article{
+				{Title: "Storm Kathleen: Amber warning as 70mph winds hit UK"},
+				{Title: "STORM KATHLEEN - amber warning as 70mph winds hit UK"},
+			},
+			expected: func(t *testing.T, d int) { assert.Equal(t, 0, d) },
+		},
+		{
+			name: "DifferentStories",
+			given: [2]model.Article{
+				{Title: "Apple fined £1.5bn by EU over music streaming"},
+				{Title: "Storm Kathleen brings 70mph winds to UK"},
+			},
+			expected: func(t *testing.T, d int) { assert.Greater(t, d, 3) },
+		},
+		{
+			name: "MissingFingerprint",
+			given: [2]model.Article{
+				{Title: "Apple fined £1.5bn by EU over music streaming"},
+				{Title: "the"},
+			},
+			expected: func(t *testing.T, d int) { assert.Equal(t, -1, d) },
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			a, bands := fingerprint(tc.given[0])
+			b, _ := fingerprint(tc.given[1])
+
+			assert.Len(t, bands, fingerprintBands)
+			tc.expected(t, distance(a, b))
+		})
+	}
+}
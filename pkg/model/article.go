@@ -4,14 +4,68 @@ import "time"
 
 type Articles []Article
 
+// Article is validated on ingestion, invalid articles are quarantined
 type Article struct {
-	ID                string     `json:"id,omitempty" bson:"_id,omitempty"`
-	Title             string     `json:"title,omitempty" bson:"title,omitempty"`
-	Descriptiopn      string     `json:"description,omitempty" bson:"description,omitempty"`
-	Link              string     `json:"link,omitempty" bson:"link,omitempty"`
+	ID           string `json:"id,omitempty" bson:"_id,omitempty" validate:"required"`
+	GUID         string `json:"guid,omitempty" bson:"guid,omitempty"`
+	Title        string `json:"title,omitempty" bson:"title,omitempty" validate:"required"`
+	Descriptiopn string `json:"description,omitempty" bson:"description,omitempty"`
+	Link         string `json:"link,omitempty" bson:"link,omitempty" validate:"required,http_url"`
+	// CanonicalLink is the link without tracking params, used for deduplication
+	CanonicalLink     string     `json:"canonicalLink,omitempty" bson:"canonicalLink,omitempty"`
 	Source            Source     `json:"source,omitempty" bson:"source,omitempty"`
 	PublishedDateTime *time.Time `json:"publishedDateTime,omitempty" bson:"publishedDateTime,omitempty"`
 	UpdatedDateTime   *time.Time `json:"updatedDateTime,omitempty" bson:"updatedDateTime,omitempty"`
+	// ContentHash is used to detect changes of title and description
+	ContentHash string `json:"-" bson:"contentHash,omitempty"`
+	// Revision is incremented every time the article is updated
+	Revision int `json:"revision,omitempty" bson:"revision,omitempty"`
+	// DescriptionHTML and DescriptionText are the sanitised versions of the description
+	// served instead of the raw markup of the feed, depending on the format requested
+	DescriptionHTML string `json:"-" bson:"descriptionHtml,omitempty"`
+	DescriptionText string `json:"-" bson:"descriptionText,omitempty"`
+	// Authors, Tags, Image, Enclosures and FeedContent are mapped from the feed item
+	Authors []Author `json:"authors,omitempty" bson:"authors,omitempty"`
+	// Tags are the categories of the feed item
+	Tags       []string    `json:"tags,omitempty" bson:"tags,omitempty"`
+	Image      *Image      `json:"image,omitempty" bson:"image,omitempty"`
+	Enclosures []Enclosure `json:"enclosures,omitempty" bson:"enclosures,omitempty"`
+	// FeedContent is the full content of the feed item (e.g. content:encoded)
+	FeedContent     string `json:"feedContent,omitempty" bson:"feedContent,omitempty"`
+	FeedContentHTML string `json:"-" bson:"feedContentHtml,omitempty"`
+	FeedContentText string `json:"-" bson:"feedContentText,omitempty"`
+	// Content is the main text extracted from the linked page, for sources opted in
+	Content            string `json:"content,omitempty" bson:"content,omitempty"`
+	WordCount          int    `json:"wordCount,omitempty" bson:"wordCount,omitempty"`
+	ReadingTimeMinutes int    `json:"readingTimeMinutes,omitempty" bson:"readingTimeMinutes,omitempty"`
+	LeadImage          string `json:"leadImage,omitempty" bson:"leadImage,omitempty"`
+	// InferredFields lists the fields missing from the feed item and inferred on ingestion
+	InferredFields []string `json:"inferredFields,omitempty" bson:"inferredFields,omitempty"`
+	// StoryID groups the versions of the same story across providers
+	StoryID string `json:"storyId,omitempty" bson:"storyId,omitempty"`
+	// Fingerprint is the SimHash of title and description (hex)
+	// FingerprintBands are its chunks, used to look up near duplicates
+	Fingerprint      string   `json:"-" bson:"fingerprint,omitempty"`
+	FingerprintBands []string `json:"-" bson:"fingerprintBands,omitempty"`
+}
+
+// Author of an article
+type Author struct {
+	Name  string `json:"name,omitempty" bson:"name,omitempty"`
+	Email string `json:"email,omitempty" bson:"email,omitempty"`
+}
+
+// Image of an article, e.g. its thumbnail
+type Image struct {
+	URL   string `json:"url" bson:"url"`
+	Title string `json:"title,omitempty" bson:"title,omitempty"`
+}
+
+// Enclosure is a media file attached to an article
+type Enclosure struct {
+	URL    string `json:"url" bson:"url"`
+	Type   string `json:"type,omitempty" bson:"type,omitempty"`
+	Length string `json:"length,omitempty" bson:"length,omitempty"`
 }
 
 // Len returns the length of Items.
@@ -21,7 +75,12 @@ func (a Articles) Len() int {
 
 // Less compares PublishedDateTime of Articles[i], Articles[k]
 // and returns true if Articles[i] is less than Articles[k].
+// Articles without PublishedDateTime come first.
 func (a Articles) Less(i, k int) bool {
+	if a[i].PublishedDateTime == nil || a[k].PublishedDateTime == nil {
+		return a[i].PublishedDateTime == nil && a[k].PublishedDateTime != nil
+	}
+
 	return a[i].PublishedDateTime.Before(
 		*a[k].PublishedDateTime,
 	)
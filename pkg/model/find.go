@@ -1,5 +1,8 @@
 package model
 
+// CollapseStory returns a single version of each story
+const CollapseStory = "story"
+
 type FindRequest struct {
 	Category string `json:"category,omitempty"`
 	Provider string `json:"provider,omitempty"`
@@ -7,6 +10,13 @@ type FindRequest struct {
 	Page     int    `json:"page,omitempty"`
 	Sort     string `json:"sort,omitempty"`
 	Order    string `json:"order,omitempty"`
+	Collapse string `json:"collapse,omitempty" validate:"omitempty,oneof=story"`
+	Author   string `json:"author,omitempty"`
+	Tag      string `json:"tag,omitempty"`
+	// Format of description and feed content, html (sanitised, default) or text
+	Format string `json:"format,omitempty" validate:"omitempty,oneof=html text"`
+	// HasImage is a string in the query params, e.g. hasImage=true
+	HasImage bool `json:"hasImage,omitempty,string"`
 }
 
 type FindResponse struct {
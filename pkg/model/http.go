@@ -0,0 +1,23 @@
+package model
+
+// HTTPSettings are the settings of the requests fetching the feed of a source
+// secrets aren't stored, they are referenced by the name of the environment variable holding them
+type HTTPSettings struct {
+	// UserAgent overrides the default user agent
+	UserAgent string `json:"userAgent,omitempty" bson:"userAgent,omitempty"`
+	// Headers are added to every request, e.g. {"Accept-Language": "en-GB"}
+	Headers map[string]string `json:"headers,omitempty" bson:"headers,omitempty"`
+	// SecretHeaders are added to every request with the value of the environment variable
+	// e.g. {"X-Api-Key": "FEED_SECRET_PARTNER_KEY"}
+	SecretHeaders map[string]string `json:"secretHeaders,omitempty" bson:"secretHeaders,omitempty"`
+	BasicAuth     *BasicAuth        `json:"basicAuth,omitempty" bson:"basicAuth,omitempty"`
+	// Proxy is the url of the outbound proxy, e.g. http://proxy.internal:3128
+	Proxy     string     `json:"proxy,omitempty" bson:"proxy,omitempty" validate:"omitempty,url"`
+	ProxyAuth *BasicAuth `json:"proxyAuth,omitempty" bson:"proxyAuth,omitempty"`
+}
+
+// BasicAuth is a username and the environment variable holding the password
+type BasicAuth struct {
+	Username    string `json:"username" bson:"username" validate:"required"`
+	PasswordEnv string `json:"passwordEnv" bson:"passwordEnv" validate:"required"`
+}
@@ -0,0 +1,49 @@
+package model
+
+import "time"
+
+const (
+	JobQueued    = "queued"
+	JobRunning   = "running"
+	JobSucceeded = "succeeded"
+	JobFailed    = "failed"
+)
+
+// Job is an asynchronous load of a feed url, or of every registered source
+type Job struct {
+	ID       string      `json:"id" bson:"_id"`
+	FeedURL  string      `json:"feedUrl,omitempty" bson:"feedUrl,omitempty"`
+	DryRun   bool        `json:"dryRun,omitempty" bson:"dryRun,omitempty"`
+	Status   string      `json:"status" bson:"status"`
+	Progress JobProgress `json:"progress" bson:"progress"`
+	// Report is updated every time a source is saved
+	Report *LoadReport `json:"report,omitempty" bson:"report,omitempty"`
+	Error  string      `json:"error,omitempty" bson:"error,omitempty"`
+	// Owner is the instance running the job, it holds the job until its lease expires
+	// and renews the lease while the job is running
+	Owner          string     `json:"owner,omitempty" bson:"owner,omitempty"`
+	LeaseExpiresAt *time.Time `json:"leaseExpiresAt,omitempty" bson:"leaseExpiresAt,omitempty"`
+	CreatedAt      time.Time  `json:"createdAt" bson:"createdAt"`
+	StartedAt      *time.Time `json:"startedAt,omitempty" bson:"startedAt,omitempty"`
+	FinishedAt     *time.Time `json:"finishedAt,omitempty" bson:"finishedAt,omitempty"`
+}
+
+// JobProgress is the number of sources saved out of the sources loaded
+type JobProgress struct {
+	Sources int `json:"sources" bson:"sources"`
+	Done    int `json:"done" bson:"done"`
+}
+
+// JobRequest lists the jobs, latest first
+// limit and page (from 0) are strings in the query params, e.g. limit=50
+type JobRequest struct {
+	Status string `json:"status,omitempty" validate:"omitempty,oneof=queued running succeeded failed"`
+	Limit  int    `json:"limit,omitempty,string" validate:"omitempty,min=1,max=1000"`
+	Page   int    `json:"page,omitempty,string" validate:"min=0"`
+}
+
+type JobResponse struct {
+	Criteria JobRequest `json:"criteria"`
+	Jobs     []Job      `json:"jobs"`
+	Total    int        `json:"total"`
+}
@@ -0,0 +1,17 @@
+package model
+
+// LinkPolicy describes how article links are canonicalised
+type LinkPolicy struct {
+	// StripParams are regular expressions matched against query param names to be removed
+	StripParams   []string `json:"stripParams,omitempty" bson:"stripParams,omitempty"`
+	ForceHTTPS    bool     `json:"forceHttps,omitempty" bson:"forceHttps,omitempty"`
+	DropFragment  bool     `json:"dropFragment,omitempty" bson:"dropFragment,omitempty"`
+	LowercaseHost bool     `json:"lowercaseHost,omitempty" bson:"lowercaseHost,omitempty"`
+}
+
+// DefaultLinkPolicy is used for providers without their own policy
+var DefaultLinkPolicy = LinkPolicy{
+	StripParams:   []string{"^utm_"},
+	DropFragment:  true,
+	LowercaseHost: true,
+}
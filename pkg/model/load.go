@@ -0,0 +1,104 @@
+package model
+
+import "time"
+
+const (
+	OutcomeCreate     = "create"
+	OutcomeUpdate     = "update"
+	OutcomeSkip       = "skip"
+	OutcomeFilter     = "filter"
+	OutcomeQuarantine = "quarantine"
+)
+
+// LoadRequest - feedUrl and dryRun are query params, e.g. dryRun=true
+// every registered source is loaded if feedUrl is not provided
+type LoadRequest struct {
+	FeedURL string `json:"feedUrl,omitempty"`
+	// DryRun reports what the load would do without writing anything
+	DryRun bool `json:"dryRun,omitempty,string"`
+}
+
+// UploadRequest is the source of an uploaded feed document
+// sourceId, feedUrl, provider, category and dryRun are query params
+// either a registered source id or the feed url identifying the source is required
+type UploadRequest struct {
+	SourceID string `json:"sourceId,omitempty" validate:"required_without=FeedURL"`
+	FeedURL  string `json:"feedUrl,omitempty" validate:"omitempty,http_url"`
+	// Provider and Category of a feed url that isn't registered, derived from the feed if not provided
+	Provider string `json:"provider,omitempty"`
+	Category string `json:"category,omitempty"`
+	DryRun   bool   `json:"dryRun,omitempty,string"`
+	// ContentType of the document, used to detect its charset
+	ContentType string `json:"-"`
+}
+
+// LoadReport describes what happened on each source of a load
+type LoadReport struct {
+	StartedAt   time.Time      `json:"startedAt"`
+	FinishedAt  time.Time      `json:"finishedAt"`
+	Sources     []SourceReport `json:"sources"`
+	Fetched     int            `json:"fetched"`
+	New         int            `json:"new"`
+	Updated     int            `json:"updated"`
+	Skipped     int            `json:"skippedDuplicates"`
+	Conflicts   int            `json:"conflicts"`
+	Filtered    int            `json:"filtered"`
+	Quarantined int            `json:"quarantined"`
+	Failed      int            `json:"failed"`
+	NotModified int            `json:"notModified"`
+	DryRun      bool           `json:"dryRun,omitempty"`
+}
+
+// SourceReport is the outcome of loading a single source
+type SourceReport struct {
+	Source  Source `json:"source"`
+	Fetched int    `json:"fetched"`
+	New     int    `json:"new"`
+	Updated int    `json:"updated"`
+	Skipped int    `json:"skippedDuplicates"`
+	// Conflicts is the number of updates lost to a concurrent load which has updated the article first
+	Conflicts int `json:"conflicts"`
+	// Filtered is the number of articles dropped by the pipeline or the rules of the source
+	Filtered int `json:"filtered"`
+	// Quarantined is the number of articles kept aside by the rules of the source
+	Quarantined int `json:"quarantined"`
+	// NotModified is set when the feed hasn't changed since the previous fetch
+	NotModified bool   `json:"notModified,omitempty"`
+	Error       string `json:"error,omitempty"`
+	// Items is what a dry run would do with every article of the feed
+	Items []ItemOutcome `json:"items,omitempty"`
+}
+
+// ItemOutcome is what a load would do with an article and why
+type ItemOutcome struct {
+	ID      string `json:"id"`
+	Title   string `json:"title,omitempty"`
+	Link    string `json:"link,omitempty"`
+	Outcome string `json:"outcome"`
+	Reason  string `json:"reason,omitempty"`
+}
+
+// NewItemOutcome returns the outcome of the article
+func NewItemOutcome(article Article, outcome, reason string) ItemOutcome {
+	return ItemOutcome{ID: article.ID, Title: article.Title, Link: article.Link, Outcome: outcome, Reason: reason}
+}
+
+// Add appends the source report and updates the totals
+func (r *LoadReport) Add(sr SourceReport) {
+	r.Sources = append(r.Sources, sr)
+	r.Fetched += sr.Fetched
+	r.New += sr.New
+	r.Updated += sr.Updated
+	r.Skipped += sr.Skipped
+	r.Conflicts += sr.Conflicts
+	r.Filtered += sr.Filtered
+	r.Quarantined += sr.Quarantined
+
+	if sr.Error != "" {
+		r.Failed++
+	}
+
+	if sr.NotModified {
+		r.NotModified++
+	}
+}
@@ -0,0 +1,8 @@
+package model
+
+// StageConfig is a stage of the ingestion pipeline of a source
+// Name is the name the stage is registered with and Options are specific to the stage
+type StageConfig struct {
+	Name    string            `json:"name" bson:"name" validate:"required"`
+	Options map[string]string `json:"options,omitempty" bson:"options,omitempty"`
+}
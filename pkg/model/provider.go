@@ -4,3 +4,21 @@ const (
 	ProviderBBC string = "bbc"
 	ProviderSky string = "sky"
 )
+
+// ProviderLinkPolicies are the link policies of known providers
+// sources can still override them with their own policy
+var ProviderLinkPolicies = map[string]LinkPolicy{
+	// e.g. ?at_medium=RSS&at_campaign=KARANGA
+	ProviderBBC: {
+		StripParams:   []string{"^at_", "^utm_"},
+		ForceHTTPS:    true,
+		DropFragment:  true,
+		LowercaseHost: true,
+	},
+	ProviderSky: {
+		StripParams:   []string{"^utm_", "^dcmp$"},
+		ForceHTTPS:    true,
+		DropFragment:  true,
+		LowercaseHost: true,
+	},
+}
@@ -0,0 +1,39 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// QuarantinedItem is an article kept aside at ingestion for review
+// either rejected by a rule or failing validation
+type QuarantinedItem struct {
+	// ID is the id of the article so an item is only quarantined once
+	ID      string  `json:"id" bson:"_id"`
+	Source  Source  `json:"source" bson:"source"`
+	Article Article `json:"article" bson:"article"`
+	Reason  string  `json:"reason" bson:"reason"`
+	// RuleID is the rule which rejected the article, if any
+	RuleID string `json:"ruleId,omitempty" bson:"ruleId,omitempty"`
+	// Raw is the feed item as parsed (json) for the items failing validation
+	Raw           json.RawMessage `json:"raw,omitempty" bson:"raw,omitempty"`
+	QuarantinedAt time.Time       `json:"quarantinedAt" bson:"quarantinedAt"`
+	// DiscardedAt is set once the item is discarded, the item is kept so the article isn't quarantined again
+	DiscardedAt *time.Time `json:"discardedAt,omitempty" bson:"discardedAt,omitempty"`
+}
+
+// QuarantineRequest lists the quarantined items, latest first
+// limit and page (from 0) are strings in the query params, e.g. limit=50
+type QuarantineRequest struct {
+	SourceID string `json:"sourceId,omitempty"`
+	Limit    int    `json:"limit,omitempty,string" validate:"omitempty,min=1,max=1000"`
+	Page     int    `json:"page,omitempty,string" validate:"min=0"`
+	// Format of the description and feed content of the articles, html (sanitised, default) or text
+	Format string `json:"format,omitempty" validate:"omitempty,oneof=html text"`
+}
+
+type QuarantineResponse struct {
+	Criteria QuarantineRequest `json:"criteria"`
+	Items    []QuarantinedItem `json:"items"`
+	Total    int               `json:"total"`
+}
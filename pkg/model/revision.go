@@ -0,0 +1,75 @@
+package model
+
+import "time"
+
+// Revision is a previous version of an article
+type Revision struct {
+	ID                string     `json:"id,omitempty" bson:"_id,omitempty"`
+	ArticleID         string     `json:"articleId,omitempty" bson:"articleId,omitempty"`
+	Revision          int        `json:"revision" bson:"revision"`
+	Title             string     `json:"title,omitempty" bson:"title,omitempty"`
+	Description       string     `json:"description,omitempty" bson:"description,omitempty"`
+	Link              string     `json:"link,omitempty" bson:"link,omitempty"`
+	ContentHash       string     `json:"-" bson:"contentHash,omitempty"`
+	PublishedDateTime *time.Time `json:"publishedDateTime,omitempty" bson:"publishedDateTime,omitempty"`
+	UpdatedDateTime   *time.Time `json:"updatedDateTime,omitempty" bson:"updatedDateTime,omitempty"`
+	// RevisedAt is when the article was replaced by the next revision
+	RevisedAt time.Time `json:"revisedAt" bson:"revisedAt"`
+}
+
+// RevisionDiff lists the changes from a revision to the next one
+type RevisionDiff struct {
+	From            int           `json:"from"`
+	To              int           `json:"to"`
+	RevisedAt       time.Time     `json:"revisedAt"`
+	UpdatedDateTime *time.Time    `json:"updatedDateTime,omitempty"`
+	Changes         []FieldChange `json:"changes"`
+}
+
+// FieldChange is the previous and new value of a field
+type FieldChange struct {
+	Field string `json:"field"`
+	From  string `json:"from"`
+	To    string `json:"to"`
+}
+
+// NewRevision returns the article as a revision
+func NewRevision(article Article, revisedAt time.Time) Revision {
+	return Revision{
+		ArticleID:         article.ID,
+		Revision:          article.Revision,
+		Title:             article.Title,
+		Description:       article.Descriptiopn,
+		Link:              article.Link,
+		ContentHash:       article.ContentHash,
+		PublishedDateTime: article.PublishedDateTime,
+		UpdatedDateTime:   article.UpdatedDateTime,
+		RevisedAt:         revisedAt,
+	}
+}
+
+// Diff returns the changes of title, description and link from the revision to the next one
+func (r Revision) Diff(next Revision) RevisionDiff {
+	diff := RevisionDiff{
+		From:            r.Revision,
+		To:              next.Revision,
+		RevisedAt:       r.RevisedAt,
+		UpdatedDateTime: next.UpdatedDateTime,
+		Changes:         make([]FieldChange, 0),
+	}
+
+	for _, field := range []struct {
+		name     string
+		from, to string
+	}{
+		{name: "title", from: r.Title, to: next.Title},
+		{name: "description", from: r.Description, to: next.Description},
+		{name: "link", from: r.Link, to: next.Link},
+	} {
+		if field.from != field.to {
+			diff.Changes = append(diff.Changes, FieldChange{Field: field.name, From: field.from, To: field.to})
+		}
+	}
+
+	return diff
+}
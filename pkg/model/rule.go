@@ -0,0 +1,46 @@
+package model
+
+const (
+	// RuleBlock rejects the articles matching the rule
+	RuleBlock = "block"
+	// RuleAllow rejects the articles matching none of the allow rules of a source
+	RuleAllow = "allow"
+)
+
+const (
+	// RuleActionDrop discards the rejected articles
+	RuleActionDrop = "drop"
+	// RuleActionQuarantine keeps the rejected articles aside for review
+	RuleActionQuarantine = "quarantine"
+)
+
+// Rule is applied to the articles of a source (or every source) at ingestion
+// an article matches a rule when it matches every condition set
+type Rule struct {
+	ID   string `json:"id,omitempty" bson:"_id,omitempty"`
+	Name string `json:"name,omitempty" bson:"name,omitempty" validate:"required"`
+	// SourceID restricts the rule to a source, rules without source are global
+	SourceID string `json:"sourceId,omitempty" bson:"sourceId,omitempty"`
+	Type     string `json:"type,omitempty" bson:"type,omitempty" validate:"required,oneof=block allow"`
+	// Action applied to the rejected articles, it defaults to drop
+	Action string `json:"action,omitempty" bson:"action,omitempty" validate:"omitempty,oneof=drop quarantine"`
+	// Title, Description and Link are regular expressions
+	// the description is matched against its plain text
+	Title       string `json:"title,omitempty" bson:"title,omitempty"`
+	Description string `json:"description,omitempty" bson:"description,omitempty"`
+	Link        string `json:"link,omitempty" bson:"link,omitempty"`
+	// Keywords are matched as whole words of the title or description, ignoring case
+	Keywords []string `json:"keywords,omitempty" bson:"keywords,omitempty"`
+	// Categories are matched against the source category and the article tags, ignoring case
+	Categories []string `json:"categories,omitempty" bson:"categories,omitempty"`
+	// OlderThan matches the articles published longer ago than the duration e.g. 72h
+	OlderThan string `json:"olderThan,omitempty" bson:"olderThan,omitempty"`
+}
+
+// DryRunResponse lists the stored articles a rule would have matched
+type DryRunResponse struct {
+	Rule     Rule      `json:"rule"`
+	Scanned  int       `json:"scanned"`
+	Matched  int       `json:"matched"`
+	Articles []Article `json:"articles"`
+}
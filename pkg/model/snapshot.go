@@ -0,0 +1,38 @@
+package model
+
+import "time"
+
+// Snapshot is the raw body of a fetched feed, as received
+// it's kept to look into a feed failing to parse and to replay its ingestion
+type Snapshot struct {
+	ID     string `json:"id" bson:"_id"`
+	Source Source `json:"source" bson:"source"`
+	// Header are the response headers of the fetch
+	Header    map[string][]string `json:"header,omitempty" bson:"header,omitempty"`
+	FetchedAt time.Time           `json:"fetchedAt" bson:"fetchedAt"`
+	// Size is the size of the body before compression
+	Size int `json:"size" bson:"size"`
+	// Body is gzip compressed
+	Body []byte `json:"-" bson:"body"`
+	// Error is why the body couldn't be parsed, if so
+	Error string `json:"error,omitempty" bson:"error,omitempty"`
+}
+
+// SnapshotRequest lists the snapshots, latest first
+// limit and page (from 0) are strings in the query params, e.g. limit=50
+type SnapshotRequest struct {
+	SourceID string `json:"sourceId,omitempty"`
+	Limit    int    `json:"limit,omitempty,string" validate:"omitempty,min=1,max=1000"`
+	Page     int    `json:"page,omitempty,string" validate:"min=0"`
+}
+
+type SnapshotResponse struct {
+	Criteria  SnapshotRequest `json:"criteria"`
+	Snapshots []Snapshot      `json:"snapshots"`
+	Total     int             `json:"total"`
+}
+
+// ReplayRequest - dryRun is a query param, e.g. dryRun=true
+type ReplayRequest struct {
+	DryRun bool `json:"dryRun,omitempty,string"`
+}
@@ -1,35 +1,123 @@
 package model
 
-const (
-	SourceBBC = "bbc.co.uk/news"
-	SourceSky = "news.sky.com"
-)
+import "time"
 
 var Sources []Source
 
 type Source struct {
-	Category string `json:"category,omitempty" bson:"category,omitempty"`
-	FeedURL  string `json:"feedUrl,omitempty" bson:"feedUrl,omitempty"`
-	Provider string `json:"provider,omitempty" bson:"provider,omitempty"`
+	ID       string `json:"id,omitempty" bson:"_id,omitempty"`
+	Category string `json:"category,omitempty" bson:"category,omitempty" validate:"required"`
+	FeedURL  string `json:"feedUrl,omitempty" bson:"feedUrl,omitempty" validate:"required,http_url"`
+	Provider string `json:"provider,omitempty" bson:"provider,omitempty" validate:"required"`
+	// AdHoc sources are registered automatically when loading a feed url that isn't registered
+	AdHoc bool `json:"adHoc,omitempty" bson:"adHoc,omitempty"`
+	// Manual sources are only loaded from uploaded documents, they aren't polled
+	Manual bool `json:"manual,omitempty" bson:"manual,omitempty"`
+	// PollIntervalSeconds overrides the scheduler default interval for this source
+	PollIntervalSeconds int `json:"pollIntervalSeconds,omitempty" bson:"pollIntervalSeconds,omitempty" validate:"omitempty,min=60"`
+	// IDStrategy is how article ids are generated, it defaults to guid
+	IDStrategy string `json:"idStrategy,omitempty" bson:"idStrategy,omitempty" validate:"omitempty,oneof=guid link"`
+	// LinkPolicy overrides the link policy of the provider
+	LinkPolicy *LinkPolicy `json:"linkPolicy,omitempty" bson:"linkPolicy,omitempty"`
+	// Pipeline are the stages run in order on the articles of the source before they are saved
+	Pipeline []StageConfig `json:"pipeline,omitempty" bson:"pipeline,omitempty" validate:"omitempty,dive"`
+	// Extract enables the extraction of the article body from the linked page
+	Extract bool `json:"extract,omitempty" bson:"extract,omitempty"`
+	// HTTP are the settings of the requests fetching the feed
+	HTTP   *HTTPSettings `json:"http,omitempty" bson:"http,omitempty"`
+	Status *SourceStatus `json:"status,omitempty" bson:"status,omitempty"`
+}
+
+// GetLinkPolicy returns the source link policy, or the provider one or the default one
+func (s Source) GetLinkPolicy() LinkPolicy {
+	if s.LinkPolicy != nil {
+		return *s.LinkPolicy
+	}
+
+	if policy, ok := ProviderLinkPolicies[s.Provider]; ok {
+		return policy
+	}
+
+	return DefaultLinkPolicy
+}
+
+const (
+	// IDStrategyGUID uses the item GUID when present, otherwise the hash of the canonical link
+	IDStrategyGUID = "guid"
+	// IDStrategyLink always uses the hash of the canonical link
+	IDStrategyLink = "link"
+)
+
+// SourceStatus is maintained by the server and ignored when creating or updating a source
+type SourceStatus struct {
+	LastRunAt *time.Time `json:"lastRunAt,omitempty" bson:"lastRunAt,omitempty"`
+	NextRunAt *time.Time `json:"nextRunAt,omitempty" bson:"nextRunAt,omitempty"`
+	LastError string     `json:"lastError,omitempty" bson:"lastError,omitempty"`
+	// ETag and LastModified are sent back on the next fetch as a conditional request
+	ETag         string `json:"etag,omitempty" bson:"etag,omitempty"`
+	LastModified string `json:"lastModified,omitempty" bson:"lastModified,omitempty"`
+	// Circuit is the live state of the feed host circuit breaker, it isn't persisted
+	Circuit *CircuitStatus `json:"circuit,omitempty" bson:"-"`
+	// Host is the live state of the request limits of the feed host, it isn't persisted
+	Host *HostStatus `json:"host,omitempty" bson:"-"`
+}
+
+const (
+	CircuitClosed   = "closed"
+	CircuitOpen     = "open"
+	CircuitHalfOpen = "half-open"
+)
+
+// CircuitStatus is the state of the circuit breaker of a feed host
+type CircuitStatus struct {
+	Host     string     `json:"host"`
+	State    string     `json:"state"`
+	Failures int        `json:"failures"`
+	RetryAt  *time.Time `json:"retryAt,omitempty"`
+}
+
+// HostStatus are the request limits of a host and its requests in flight
+type HostStatus struct {
+	Host string `json:"host"`
+	// MinInterval is the min time between two requests, e.g. 1s
+	MinInterval string `json:"minInterval"`
+	// MaxConcurrent is the max number of requests in flight, unlimited if 0
+	MaxConcurrent int `json:"maxConcurrent"`
+	// CrawlDelay is the Crawl-delay of the robots.txt of the host
+	// it only spaces out the fetches of the article pages
+	CrawlDelay    string     `json:"crawlDelay,omitempty"`
+	Active        int        `json:"active"`
+	NextRequestAt *time.Time `json:"nextRequestAt,omitempty"`
+}
+
+// Reference returns the subset of the source that is embedded in each article
+func (s Source) Reference() Source {
+	return Source{
+		ID:       s.ID,
+		Category: s.Category,
+		FeedURL:  s.FeedURL,
+		Provider: s.Provider,
+	}
 }
 
-var DefaultSources = map[string]Source{
-	"bbc.co.uk/news/uk": {
+// DefaultSources are used to seed the source registry on first start
+var DefaultSources = []Source{
+	{
 		Category: CategoryUK,
 		FeedURL:  "https://feeds.bbci.co.uk/news/uk/rss.xml",
 		Provider: ProviderBBC,
 	},
-	"bbc.co.uk/news/technology": {
+	{
 		Category: CategoryTechnology,
 		FeedURL:  "https://feeds.bbci.co.uk/news/technology/rss.xml",
 		Provider: ProviderBBC,
 	},
-	"news.sky.com/uk": {
+	{
 		Category: CategoryUK,
 		FeedURL:  "https://feeds.skynews.com/feeds/rss/uk.xml",
 		Provider: ProviderSky,
 	},
-	"news.sky.com/technology": {
+	{
 		Category: CategoryTechnology,
 		FeedURL:  "https://feeds.skynews.com/feeds/rss/technology.xml",
 		Provider: ProviderSky,
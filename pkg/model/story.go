@@ -0,0 +1,10 @@
+package model
+
+// Story lists every provider's version of the same story
+type Story struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+	// Providers that have published a version of the story
+	Providers []string  `json:"providers"`
+	Articles  []Article `json:"articles"`
+}
@@ -0,0 +1,145 @@
+// Package pipeline runs the articles of a source through ordered stages
+// between the fetch of the feed and the save of the articles.
+//
+// A stage can filter articles out, transform them or enrich them with extra data.
+// Stages are registered by name and referenced by the pipeline of a source,
+// e.g. to register a stage when embedding the package:
+//
+//	pipeline.Register("language", func(options map[string]string) (pipeline.Stage, error) {
+//		return pipeline.Enrich("language", 4, detectLanguage), nil
+//	})
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"go-news-feed/pkg/model"
+)
+
+// Stage processes the articles of a source
+// it returns the articles to pass to the next stage
+type Stage interface {
+	Name() string
+	Process(ctx context.Context, source model.Source, articles []model.Article) ([]model.Article, error)
+}
+
+// Pipeline is an ordered list of stages
+type Pipeline []Stage
+
+// Run processes the articles through every stage in order
+// it stops at the first stage failing
+func (p Pipeline) Run(ctx context.Context, source model.Source, articles []model.Article) ([]model.Article, error) {
+	var err error
+
+	for _, stage := range p {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		articles, err = stage.Process(ctx, source, articles)
+		if err != nil {
+			return nil, fmt.Errorf("stage %s: %w", stage.Name(), err)
+		}
+	}
+
+	return articles, nil
+}
+
+// FilterFunc returns true for the articles to keep
+type FilterFunc func(ctx context.Context, source model.Source, article model.Article) (bool, error)
+
+// TransformFunc changes an article in place
+type TransformFunc func(ctx context.Context, source model.Source, article *model.Article) error
+
+// Filter returns a stage keeping the articles the function returns true for
+func Filter(name string, fn FilterFunc) Stage {
+	return filterStage{name: name, fn: fn}
+}
+
+// Transform returns a stage changing every article in order
+func Transform(name string, fn TransformFunc) Stage {
+	return transformStage{name: name, fn: fn, workers: 1}
+}
+
+// Enrich returns a stage changing the articles concurrently
+// e.g. when every article requires a call to an external service
+func Enrich(name string, workers int, fn TransformFunc) Stage {
+	return transformStage{name: name, fn: fn, workers: max(1, workers)}
+}
+
+type filterStage struct {
+	name string
+	fn   FilterFunc
+}
+
+func (s filterStage) Name() string {
+	return s.name
+}
+
+func (s filterStage) Process(ctx context.Context, source model.Source, articles []model.Article) ([]model.Article, error) {
+	kept := make([]model.Article, 0, len(articles))
+
+	for _, article := range articles {
+		keep, err := s.fn(ctx, source, article)
+		if err != nil {
+			return nil, err
+		}
+
+		if keep {
+			kept = append(kept, article)
+		}
+	}
+
+	return kept, nil
+}
+
+type transformStage struct {
+	name    string
+	fn      TransformFunc
+	workers int
+}
+
+func (s transformStage) Name() string {
+	return s.name
+}
+
+func (s transformStage) Process(ctx context.Context, source model.Source, articles []model.Article) ([]model.Article, error) {
+	transformed := make([]model.Article, len(articles))
+	copy(transformed, articles)
+
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+		indexes  = make(chan int)
+	)
+
+	for range min(s.workers, max(1, len(transformed))) {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for i := range indexes {
+				if err := s.fn(ctx, source, &transformed[i]); err != nil {
+					once.Do(func() { firstErr = err })
+				}
+			}
+		}()
+	}
+
+	for i := range transformed {
+		indexes <- i
+	}
+
+	close(indexes)
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	return transformed, nil
+}
@@ -0,0 +1,121 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go-news-feed/pkg/model"
+)
+
+func TestRegistry(t *testing.T) {
+	registry := NewRegistry()
+
+	err := registry.Register("upper", func(map[string]string) (Stage, error) {
+		return Transform("upper", func(_ context.Context, _ model.Source, article *model.Article) error {
+			article.Title += "!"
+			return nil
+		}), nil
+	})
+	require.NoError(t, err)
+
+	assert.ErrorIs(t, registry.Register("upper", nil), ErrAlreadyRegistered)
+	assert.ErrorIs(t, registry.Register("max-age", nil), ErrAlreadyRegistered)
+
+	_, err = registry.Build([]model.StageConfig{{Name: "missing"}})
+	assert.ErrorIs(t, err, ErrUnknownStage)
+
+	_, err = registry.Build([]model.StageConfig{{Name: "require", Options: map[string]string{"fields": "colour"}}})
+	assert.Error(t, err)
+
+	p, err := registry.Build([]model.StageConfig{
+		{Name: "require", Options: map[string]string{"fields": "description"}},
+		{Name: "upper"},
+		{Name: "add-tags", Options: map[string]string{"tags": "local, london"}},
+	})
+	require.NoError(t, err)
+
+	articles, err := p.Run(context.Background(), model.Source{}, []model.Article{
+		{ID: "1", Title: "kept", Descriptiopn: "description", Tags: []string{"london"}},
+		{ID: "2", Title: "dropped"},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []model.Article{
+		{ID: "1", Title: "kept!", Descriptiopn: "description", Tags: []string{"london", "local"}},
+	}, articles)
+}
+
+func TestBuiltinStages(t *testing.T) {
+	now := time.Now()
+	old := now.Add(-96 * time.Hour)
+
+	testCases := []struct {
+		name     string
+		given    model.StageConfig
+		expected []string
+	}{
+		{
+			name:     "MaxAge",
+			given:    model.StageConfig{Name: "max-age", Options: map[string]string{"age": "72h"}},
+			expected: []string{"recent", "no image", "no date"},
+		},
+		{
+			name:     "RequireImage",
+			given:    model.StageConfig{Name: "require", Options: map[string]string{"fields": "image"}},
+			expected: []string{"recent", "old"},
+		},
+		{
+			name:     "RequireTitleAndAuthor",
+			given:    model.StageConfig{Name: "require", Options: map[string]string{"fields": "title,author"}},
+			expected: []string{"old"},
+		},
+	}
+
+	articles := []model.Article{
+		{ID: "recent", Title: "recent", PublishedDateTime: &now, Image: &model.Image{URL: "https://example.com/1.jpg"}},
+		{ID: "old", Title: "old", PublishedDateTime: &old, Image: &model.Image{URL: "https://example.com/2.jpg"}, Authors: []model.Author{{Name: "A"}}},
+		{ID: "no image", PublishedDateTime: &now},
+		{ID: "no date"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			p, err := NewRegistry().Build([]model.StageConfig{tc.given})
+			require.NoError(t, err)
+
+			processed, err := p.Run(context.Background(), model.Source{}, articles)
+			require.NoError(t, err)
+
+			ids := make([]string, len(processed))
+			for i, article := range processed {
+				ids[i] = article.ID
+			}
+
+			assert.Equal(t, tc.expected, ids)
+		})
+	}
+}
+
+func TestRunStopsOnError(t *testing.T) {
+	var calls int
+
+	p := Pipeline{
+		Enrich("fail", 4, func(context.Context, model.Source, *model.Article) error {
+			return errors.New("service unavailable")
+		}),
+		Filter("never", func(context.Context, model.Source, model.Article) (bool, error) {
+			calls++
+			return true, nil
+		}),
+	}
+
+	_, err := p.Run(context.Background(), model.Source{}, []model.Article{{ID: "1"}, {ID: "2"}})
+
+	assert.EqualError(t, err, "stage fail: service unavailable")
+	assert.Zero(t, calls)
+}
@@ -0,0 +1,81 @@
+package pipeline
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"go-news-feed/pkg/model"
+)
+
+var (
+	ErrAlreadyRegistered = errors.New("stage already registered")
+	ErrUnknownStage      = errors.New("unknown stage")
+)
+
+// Factory builds a stage from the options of the source pipeline
+type Factory func(options map[string]string) (Stage, error)
+
+// Registry maps stage names to their factory
+type Registry struct {
+	mu        sync.RWMutex
+	factories map[string]Factory
+}
+
+// DefaultRegistry is used by the server to build the pipeline of sources
+var DefaultRegistry = NewRegistry()
+
+// NewRegistry - constructor
+// the registry comes with the built-in stages
+func NewRegistry() *Registry {
+	r := &Registry{factories: make(map[string]Factory)}
+
+	for name, factory := range builtins {
+		r.factories[name] = factory
+	}
+
+	return r
+}
+
+// Register adds a stage to the default registry
+func Register(name string, factory Factory) error {
+	return DefaultRegistry.Register(name, factory)
+}
+
+// Register adds a stage to the registry, names must be unique
+func (r *Registry) Register(name string, factory Factory) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.factories[name]; ok {
+		return fmt.Errorf("%w: %s", ErrAlreadyRegistered, name)
+	}
+
+	r.factories[name] = factory
+
+	return nil
+}
+
+// Build returns the pipeline of the stages configured
+func (r *Registry) Build(configs []model.StageConfig) (Pipeline, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	pipeline := make(Pipeline, 0, len(configs))
+
+	for _, config := range configs {
+		factory, ok := r.factories[config.Name]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownStage, config.Name)
+		}
+
+		stage, err := factory(config.Options)
+		if err != nil {
+			return nil, fmt.Errorf("stage %s: %w", config.Name, err)
+		}
+
+		pipeline = append(pipeline, stage)
+	}
+
+	return pipeline, nil
+}
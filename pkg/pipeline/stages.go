@@ -0,0 +1,106 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"go-news-feed/pkg/model"
+)
+
+// builtins are the stages every registry comes with
+var builtins = map[string]Factory{
+	"max-age":  newMaxAge,
+	"require":  newRequire,
+	"add-tags": newAddTags,
+}
+
+// newMaxAge drops the articles published longer ago than the age option
+// e.g. {"age": "72h"}
+func newMaxAge(options map[string]string) (Stage, error) {
+	age, err := time.ParseDuration(options["age"])
+	if err != nil || age <= 0 {
+		return nil, fmt.Errorf("invalid age %q", options["age"])
+	}
+
+	return Filter("max-age", func(_ context.Context, _ model.Source, article model.Article) (bool, error) {
+		return article.PublishedDateTime == nil || time.Since(*article.PublishedDateTime) <= age, nil
+	}), nil
+}
+
+// newRequire drops the articles missing any of the fields option
+// e.g. {"fields": "description,image"}
+func newRequire(options map[string]string) (Stage, error) {
+	fields := splitList(options["fields"])
+	if len(fields) == 0 {
+		return nil, errors.New("fields are required")
+	}
+
+	for _, field := range fields {
+		if !slices.Contains([]string{"title", "description", "link", "image", "author"}, field) {
+			return nil, fmt.Errorf("unsupported field %q", field)
+		}
+	}
+
+	return Filter("require", func(_ context.Context, _ model.Source, article model.Article) (bool, error) {
+		for _, field := range fields {
+			if !hasField(article, field) {
+				return false, nil
+			}
+		}
+
+		return true, nil
+	}), nil
+}
+
+// newAddTags adds the tags option to every article
+// e.g. {"tags": "local,london"}
+func newAddTags(options map[string]string) (Stage, error) {
+	tags := splitList(options["tags"])
+	if len(tags) == 0 {
+		return nil, errors.New("tags are required")
+	}
+
+	return Transform("add-tags", func(_ context.Context, _ model.Source, article *model.Article) error {
+		for _, tag := range tags {
+			if !slices.Contains(article.Tags, tag) {
+				article.Tags = append(article.Tags, tag)
+			}
+		}
+
+		return nil
+	}), nil
+}
+
+func hasField(article model.Article, field string) bool {
+	switch field {
+	case "title":
+		return article.Title != ""
+	case "description":
+		return article.Descriptiopn != ""
+	case "link":
+		return article.Link != ""
+	case "image":
+		return article.Image != nil
+	case "author":
+		return len(article.Authors) > 0
+	default:
+		return false
+	}
+}
+
+// splitList splits a comma separated option
+func splitList(value string) []string {
+	values := make([]string, 0)
+
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+
+	return values
+}